@@ -1,6 +1,7 @@
 package config
 
 import (
+	"backendForKeenEye/pkg/notifier"
 	"backendForKeenEye/pkg/postgres"
 	"fmt"
 
@@ -11,10 +12,20 @@ import (
 
 type (
 	Config struct {
-		Http       `mapstructure:"http"`
-		Encryption `mapstructure:"encryption"`
-		Postgres   postgres.Config `mapstructure:"pg"`
-		JWT        `mapstructure:"jwt"`
+		Http           `mapstructure:"http"`
+		Encryption     `mapstructure:"encryption"`
+		PasswordPolicy `mapstructure:"password_policy"`
+		Postgres       postgres.Config `mapstructure:"pg"`
+		JWT            `mapstructure:"jwt"`
+		PasswordReset  `mapstructure:"password_reset"`
+		Notifier       `mapstructure:"notifier"`
+		BruteForce     `mapstructure:"brute_force"`
+		Permissions    `mapstructure:"permissions"`
+		Retention      `mapstructure:"retention"`
+		LegacyApi      `mapstructure:"legacy_api"`
+		Export         `mapstructure:"export"`
+		Bulk           `mapstructure:"bulk"`
+		Idempotency    `mapstructure:"idempotency"`
 	}
 
 	Postgres struct {
@@ -30,19 +41,104 @@ type (
 		RetryConnectionTimeout  time.Duration `mapstructure:"retry_connection_timeout"`
 	}
 
+	// Http.TrustedProxies lists the addresses or CIDR ranges of the reverse
+	// proxies whose X-Forwarded-For header is believed. With none the client
+	// address is the address of the connection.
 	Http struct {
-		Host string `mapstructure:"host"`
-		Port string `mapstructure:"port"`
+		Host           string   `mapstructure:"host"`
+		Port           string   `mapstructure:"port"`
+		TrustedProxies []string `mapstructure:"trusted_proxies"`
 	}
 
 	Encryption struct {
-		Salt int `mapstructure:"salt_length"`
+		Algorithm  string `mapstructure:"algorithm"`
+		BcryptCost int    `mapstructure:"bcrypt_cost"`
+		Argon2     `mapstructure:"argon2"`
+	}
+
+	Argon2 struct {
+		Memory      uint32 `mapstructure:"memory"`
+		Iterations  uint32 `mapstructure:"iterations"`
+		Parallelism uint8  `mapstructure:"parallelism"`
+		KeyLength   uint32 `mapstructure:"key_length"`
+		SaltLength  uint32 `mapstructure:"salt_length"`
+	}
+
+	PasswordPolicy struct {
+		MinLength        int    `mapstructure:"min_length"`
+		MaxLength        int    `mapstructure:"max_length"`
+		RequireLowercase bool   `mapstructure:"require_lowercase"`
+		RequireUppercase bool   `mapstructure:"require_uppercase"`
+		RequireDigit     bool   `mapstructure:"require_digit"`
+		RequireSymbol    bool   `mapstructure:"require_symbol"`
+		DenyListPath     string `mapstructure:"deny_list_path"`
 	}
 
 	JWT struct {
-		Key         string        `mapstructure:"key"`
-		AccessTime  time.Duration `mapstructure:"access_time"`
-		RefreshTime time.Duration `mapstructure:"refresh_time"`
+		Key                  string        `mapstructure:"key"`
+		AccessTime           time.Duration `mapstructure:"access_time"`
+		RefreshTime          time.Duration `mapstructure:"refresh_time"`
+		DenylistSyncInterval time.Duration `mapstructure:"denylist_sync_interval"`
+	}
+
+	// PasswordReset.IpLimit caps the reset requests per client address within
+	// IpWindow.
+	PasswordReset struct {
+		TokenTTL   time.Duration `mapstructure:"token_ttl"`
+		LinkFormat string        `mapstructure:"link_format"`
+		IpLimit    int           `mapstructure:"ip_limit"`
+		IpWindow   time.Duration `mapstructure:"ip_window"`
+	}
+
+	BruteForce struct {
+		Store            string        `mapstructure:"store"`
+		MaxLoginFailures int           `mapstructure:"max_login_failures"`
+		MaxIpFailures    int           `mapstructure:"max_ip_failures"`
+		FailureWindow    time.Duration `mapstructure:"failure_window"`
+		LockoutDuration  time.Duration `mapstructure:"lockout_duration"`
+		BaseDelay        time.Duration `mapstructure:"base_delay"`
+		MaxDelay         time.Duration `mapstructure:"max_delay"`
+	}
+
+	Permissions struct {
+		SyncInterval time.Duration `mapstructure:"sync_interval"`
+	}
+
+	Retention struct {
+		DeletedTTL    time.Duration `mapstructure:"deleted_ttl"`
+		PurgeInterval time.Duration `mapstructure:"purge_interval"`
+	}
+
+	// LegacyApi dates the routes kept as aliases of /api/v1, as YYYY-MM-DD.
+	LegacyApi struct {
+		DeprecatedAt string `mapstructure:"deprecated_at"`
+		Sunset       string `mapstructure:"sunset"`
+	}
+
+	// Export points at the TrueType font PDF exports are set in. It must
+	// cover Cyrillic, as DejaVu Sans does.
+	Export struct {
+		FontPath string `mapstructure:"font_path"`
+	}
+
+	// Bulk caps the number of records a single bulk request may touch.
+	Bulk struct {
+		MaxItems int `mapstructure:"max_items"`
+	}
+
+	// Idempotency sets how long the responses to requests sent with an
+	// Idempotency-Key are kept for replay, and how long an unfinished request
+	// holds its key.
+	Idempotency struct {
+		TTL             time.Duration `mapstructure:"ttl"`
+		LockTimeout     time.Duration `mapstructure:"lock_timeout"`
+		CleanupInterval time.Duration `mapstructure:"cleanup_interval"`
+	}
+
+	Notifier struct {
+		Type    string              `mapstructure:"type"`
+		LogPath string              `mapstructure:"log_path"`
+		SMTP    notifier.SMTPConfig `mapstructure:"smtp"`
 	}
 )
 
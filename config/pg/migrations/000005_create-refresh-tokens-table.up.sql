@@ -0,0 +1,14 @@
+CREATE TABLE refresh_tokens
+(
+    id          varchar(64) primary key,
+    user_id     int references users (id) on delete cascade,
+    family_id   varchar(64) not null,
+    expires_at  timestamptz not null,
+    revoked     bool default false,
+    replaced_by varchar(64),
+    created_at  timestamptz default now()
+);
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+
+CREATE INDEX refresh_tokens_user_id_idx ON refresh_tokens (user_id);
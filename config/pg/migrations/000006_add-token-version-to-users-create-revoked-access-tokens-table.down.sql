@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS revoked_access_tokens;
+
+ALTER TABLE users
+    drop column if exists token_version;
@@ -0,0 +1,11 @@
+ALTER TABLE users
+    add column token_version int not null default 0;
+
+CREATE TABLE revoked_access_tokens
+(
+    id         varchar(64) primary key,
+    user_id    int references users (id) on delete cascade,
+    expires_at timestamptz not null
+);
+
+CREATE INDEX revoked_access_tokens_expires_at_idx ON revoked_access_tokens (expires_at);
@@ -0,0 +1,2 @@
+ALTER TABLE users
+    drop column if exists must_change_password;
@@ -0,0 +1,2 @@
+ALTER TABLE users
+    add column must_change_password bool not null default false;
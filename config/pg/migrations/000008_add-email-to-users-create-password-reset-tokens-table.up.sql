@@ -0,0 +1,14 @@
+ALTER TABLE users
+    add column email varchar(256);
+
+CREATE TABLE password_reset_tokens
+(
+    id         int generated always as identity primary key,
+    user_id    int references users (id) on delete cascade,
+    token_hash varchar(64) not null unique,
+    expires_at timestamptz not null,
+    used_at    timestamptz,
+    created_at timestamptz default now()
+);
+
+CREATE INDEX password_reset_tokens_user_id_idx ON password_reset_tokens (user_id);
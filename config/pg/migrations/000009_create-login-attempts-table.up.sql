@@ -0,0 +1,7 @@
+CREATE TABLE login_attempts
+(
+    key          varchar(320) primary key,
+    failures     int         not null default 0,
+    last_failure timestamptz not null,
+    locked_until timestamptz
+);
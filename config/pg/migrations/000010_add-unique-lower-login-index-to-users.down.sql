@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS users_login_lower_key;
+
+ALTER TABLE users
+    ALTER COLUMN login DROP NOT NULL;
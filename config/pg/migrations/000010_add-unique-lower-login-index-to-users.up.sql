@@ -0,0 +1,41 @@
+-- Logins used to be unique only by convention. Keep the oldest account for
+-- every case-insensitive duplicate and suffix the others with their id so the
+-- index can be built; affected users have to be told their new login. A
+-- suffixed login may be taken as well, e.g. by an existing ivan_7, so further
+-- suffixes are tried until the login is free.
+DO
+$$
+    DECLARE
+        duplicate record;
+        suffix    text;
+        candidate text;
+        attempt   int;
+    BEGIN
+        FOR duplicate IN SELECT u.id, u.login
+                         FROM users u
+                         WHERE EXISTS (SELECT 1
+                                       FROM users o
+                                       WHERE lower(o.login) = lower(u.login)
+                                         AND o.id < u.id)
+                         ORDER BY u.id
+            LOOP
+                attempt := 1;
+                LOOP
+                    suffix := '_' || duplicate.id;
+                    IF attempt > 1 THEN
+                        suffix := suffix || '_' || attempt;
+                    END IF;
+                    candidate := left(duplicate.login, 256 - length(suffix)) || suffix;
+                    EXIT WHEN NOT EXISTS (SELECT 1 FROM users WHERE lower(login) = lower(candidate));
+                    attempt := attempt + 1;
+                END LOOP;
+
+                UPDATE users SET login = candidate WHERE id = duplicate.id;
+            END LOOP;
+    END
+$$;
+
+ALTER TABLE users
+    ALTER COLUMN login SET NOT NULL;
+
+CREATE UNIQUE INDEX users_login_lower_key ON users (lower(login));
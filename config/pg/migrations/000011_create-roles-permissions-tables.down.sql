@@ -0,0 +1,6 @@
+ALTER TABLE users
+    drop constraint if exists users_role_fkey;
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
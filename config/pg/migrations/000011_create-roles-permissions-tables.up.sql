@@ -0,0 +1,69 @@
+CREATE TABLE roles
+(
+    name        varchar(20) primary key,
+    description varchar(256)
+);
+
+CREATE TABLE permissions
+(
+    name        varchar(64) primary key,
+    description varchar(256)
+);
+
+-- scope limits a grant to some resources:
+--   all   - every resource
+--   group - resources of groups the user teaches or studies in
+--   self  - the user's own records
+CREATE TABLE role_permissions
+(
+    role       varchar(20) references roles (name) on delete cascade,
+    permission varchar(64) references permissions (name) on delete cascade,
+    scope      varchar(10) not null check (scope in ('all', 'group', 'self')),
+    primary key (role, permission)
+);
+
+INSERT INTO roles (name, description)
+VALUES ('admin', 'Administrator'),
+       ('teacher', 'Teacher'),
+       ('student', 'Student');
+
+INSERT INTO permissions (name, description)
+VALUES ('users.create', 'Create users'),
+       ('users.revoke_sessions', 'Sign users out everywhere'),
+       ('users.reset_password', 'Reset passwords of users'),
+       ('lockouts.read', 'List locked logins and addresses'),
+       ('lockouts.unlock', 'Unlock logins and addresses'),
+       ('roles.manage', 'Read and edit roles and their permissions'),
+       ('students.read', 'Read students'),
+       ('students.update', 'Update students'),
+       ('students.delete', 'Delete students'),
+       ('groups.students.read', 'List students of a group'),
+       ('teachers.read', 'Read teachers'),
+       ('teachers.update', 'Update teachers'),
+       ('teachers.delete', 'Delete teachers'),
+       ('admins.read', 'Read admins'),
+       ('admins.update', 'Update admins'),
+       ('admins.delete', 'Delete admins'),
+       ('groups.create', 'Create groups'),
+       ('groups.read', 'Read groups'),
+       ('groups.update', 'Update groups'),
+       ('groups.delete', 'Delete groups');
+
+INSERT INTO role_permissions (role, permission, scope)
+SELECT 'admin', name, 'all'
+FROM permissions;
+
+INSERT INTO role_permissions (role, permission, scope)
+VALUES ('teacher', 'students.read', 'group'),
+       ('teacher', 'students.update', 'group'),
+       ('teacher', 'groups.students.read', 'group'),
+       ('teacher', 'groups.read', 'group'),
+       ('teacher', 'teachers.read', 'self'),
+       ('teacher', 'teachers.update', 'self'),
+       ('student', 'students.read', 'self'),
+       ('student', 'students.update', 'self'),
+       ('student', 'groups.students.read', 'group'),
+       ('student', 'groups.read', 'group');
+
+ALTER TABLE users
+    add constraint users_role_fkey foreign key (role) references roles (name) on update cascade;
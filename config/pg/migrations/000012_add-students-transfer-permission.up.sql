@@ -0,0 +1,8 @@
+INSERT INTO permissions (name, description)
+VALUES ('students.transfer', 'Move students between groups');
+
+-- Teachers may only move students between groups they teach; moving a student
+-- anywhere else needs a role with the "all" scope.
+INSERT INTO role_permissions (role, permission, scope)
+VALUES ('admin', 'students.transfer', 'all'),
+       ('teacher', 'students.transfer', 'group');
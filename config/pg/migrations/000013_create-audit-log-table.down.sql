@@ -0,0 +1,5 @@
+DELETE FROM permissions
+WHERE name = 'audit.read';
+
+DROP TABLE IF EXISTS audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
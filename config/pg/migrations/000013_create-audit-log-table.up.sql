@@ -0,0 +1,37 @@
+CREATE TABLE audit_log
+(
+    id          bigint generated always as identity primary key,
+    actor_id    int,
+    actor_role  varchar(20),
+    action      varchar(64)  not null,
+    entity_type varchar(64)  not null,
+    entity_id   varchar(320) not null,
+    before      jsonb,
+    after       jsonb,
+    request_id  varchar(64),
+    ip          varchar(45),
+    created_at  timestamptz  not null default now()
+);
+
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
+CREATE INDEX audit_log_actor_id_idx ON audit_log (actor_id, created_at);
+CREATE INDEX audit_log_entity_idx ON audit_log (entity_type, entity_id, created_at);
+
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE OR TRUNCATE
+    ON audit_log
+    FOR EACH STATEMENT
+EXECUTE FUNCTION audit_log_append_only();
+
+INSERT INTO permissions (name, description)
+VALUES ('audit.read', 'Read the audit log');
+
+INSERT INTO role_permissions (role, permission, scope)
+VALUES ('admin', 'audit.read', 'all');
@@ -0,0 +1,11 @@
+DELETE FROM permissions
+WHERE name IN ('trash.manage', 'trash.purge');
+
+ALTER TABLE students
+    drop column if exists deleted_at;
+ALTER TABLE teachers
+    drop column if exists deleted_at;
+ALTER TABLE admins
+    drop column if exists deleted_at;
+ALTER TABLE groups
+    drop column if exists deleted_at;
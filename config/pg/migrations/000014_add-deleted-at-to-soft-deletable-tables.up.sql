@@ -0,0 +1,28 @@
+ALTER TABLE students
+    add column deleted_at timestamptz;
+ALTER TABLE teachers
+    add column deleted_at timestamptz;
+ALTER TABLE admins
+    add column deleted_at timestamptz;
+ALTER TABLE groups
+    add column deleted_at timestamptz;
+
+-- the real deletion time of older records is unknown; start their retention
+-- period now
+UPDATE students SET deleted_at = now() WHERE is_deleted;
+UPDATE teachers SET deleted_at = now() WHERE is_deleted;
+UPDATE admins SET deleted_at = now() WHERE is_deleted;
+UPDATE groups SET deleted_at = now() WHERE is_deleted;
+
+CREATE INDEX students_deleted_at_idx ON students (deleted_at) WHERE is_deleted;
+CREATE INDEX teachers_deleted_at_idx ON teachers (deleted_at) WHERE is_deleted;
+CREATE INDEX admins_deleted_at_idx ON admins (deleted_at) WHERE is_deleted;
+CREATE INDEX groups_deleted_at_idx ON groups (deleted_at) WHERE is_deleted;
+
+INSERT INTO permissions (name, description)
+VALUES ('trash.manage', 'List and restore deleted records'),
+       ('trash.purge', 'Permanently delete deleted records');
+
+INSERT INTO role_permissions (role, permission, scope)
+VALUES ('admin', 'trash.manage', 'all'),
+       ('admin', 'trash.purge', 'all');
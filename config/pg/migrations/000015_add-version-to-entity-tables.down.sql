@@ -0,0 +1,15 @@
+ALTER TABLE users
+    drop column if exists version,
+    drop column if exists updated_at;
+ALTER TABLE students
+    drop column if exists version,
+    drop column if exists updated_at;
+ALTER TABLE teachers
+    drop column if exists version,
+    drop column if exists updated_at;
+ALTER TABLE admins
+    drop column if exists version,
+    drop column if exists updated_at;
+ALTER TABLE groups
+    drop column if exists version,
+    drop column if exists updated_at;
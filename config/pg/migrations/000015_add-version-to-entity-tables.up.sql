@@ -0,0 +1,15 @@
+ALTER TABLE users
+    add column version    int         not null default 1,
+    add column updated_at timestamptz not null default now();
+ALTER TABLE students
+    add column version    int         not null default 1,
+    add column updated_at timestamptz not null default now();
+ALTER TABLE teachers
+    add column version    int         not null default 1,
+    add column updated_at timestamptz not null default now();
+ALTER TABLE admins
+    add column version    int         not null default 1,
+    add column updated_at timestamptz not null default now();
+ALTER TABLE groups
+    add column version    int         not null default 1,
+    add column updated_at timestamptz not null default now();
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS students_fio_id_idx;
+DROP INDEX IF EXISTS students_group_id_id_idx;
+DROP INDEX IF EXISTS teachers_fio_id_idx;
+DROP INDEX IF EXISTS groups_name_id_idx;
+DROP INDEX IF EXISTS groups_teacher_id_id_idx;
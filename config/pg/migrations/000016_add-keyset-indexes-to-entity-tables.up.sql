@@ -0,0 +1,7 @@
+-- lists are read page by page in (sort field, id) order; the sort fields are
+-- coalesced so that NULLs take part in the keyset comparisons
+CREATE INDEX students_fio_id_idx ON students (coalesce(fio, ''), id) WHERE NOT is_deleted;
+CREATE INDEX students_group_id_id_idx ON students (coalesce(group_id, 0), id) WHERE NOT is_deleted;
+CREATE INDEX teachers_fio_id_idx ON teachers (coalesce(fio, ''), id) WHERE NOT is_deleted;
+CREATE INDEX groups_name_id_idx ON groups (coalesce(name, ''), id) WHERE NOT is_deleted;
+CREATE INDEX groups_teacher_id_id_idx ON groups (coalesce(teacher_id, 0), id) WHERE NOT is_deleted;
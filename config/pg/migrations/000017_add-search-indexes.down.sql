@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS students_fio_trgm_idx;
+DROP INDEX IF EXISTS students_fio_tsv_idx;
+DROP INDEX IF EXISTS students_phone_trgm_idx;
+DROP INDEX IF EXISTS teachers_fio_trgm_idx;
+DROP INDEX IF EXISTS teachers_fio_tsv_idx;
+DROP INDEX IF EXISTS teachers_phone_trgm_idx;
+DROP INDEX IF EXISTS groups_name_trgm_idx;
+DROP INDEX IF EXISTS groups_name_tsv_idx;
+DROP INDEX IF EXISTS users_login_trgm_idx;
+
+DROP FUNCTION IF EXISTS search_digits(text);
+DROP FUNCTION IF EXISTS search_normalize(text);
@@ -0,0 +1,27 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- search_normalize makes text case-insensitive and folds ё into е, so that
+-- "Семён", "СЕМЕН" and "семен" are all found by the same query
+CREATE FUNCTION search_normalize(text) RETURNS text AS
+$$
+SELECT replace(lower(coalesce($1, '')), 'ё', 'е');
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- search_digits keeps only the digits of a phone number
+CREATE FUNCTION search_digits(text) RETURNS text AS
+$$
+SELECT regexp_replace(coalesce($1, ''), '\D', '', 'g');
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+CREATE INDEX students_fio_trgm_idx ON students USING gin (search_normalize(fio) gin_trgm_ops) WHERE NOT is_deleted;
+CREATE INDEX students_fio_tsv_idx ON students USING gin (to_tsvector('simple', search_normalize(fio))) WHERE NOT is_deleted;
+CREATE INDEX students_phone_trgm_idx ON students USING gin (search_digits(phone_number) gin_trgm_ops) WHERE NOT is_deleted;
+
+CREATE INDEX teachers_fio_trgm_idx ON teachers USING gin (search_normalize(fio) gin_trgm_ops) WHERE NOT is_deleted;
+CREATE INDEX teachers_fio_tsv_idx ON teachers USING gin (to_tsvector('simple', search_normalize(fio))) WHERE NOT is_deleted;
+CREATE INDEX teachers_phone_trgm_idx ON teachers USING gin (search_digits(phone_number) gin_trgm_ops) WHERE NOT is_deleted;
+
+CREATE INDEX groups_name_trgm_idx ON groups USING gin (search_normalize(name) gin_trgm_ops) WHERE NOT is_deleted;
+CREATE INDEX groups_name_tsv_idx ON groups USING gin (to_tsvector('simple', search_normalize(name))) WHERE NOT is_deleted;
+
+CREATE INDEX users_login_trgm_idx ON users USING gin (search_normalize(login) gin_trgm_ops);
@@ -0,0 +1,14 @@
+CREATE TABLE idempotency_keys
+(
+    user_id     int          not null references users (id) on delete cascade,
+    key         varchar(255) not null,
+    fingerprint char(64)     not null,
+    status      int,
+    header      jsonb,
+    body        bytea,
+    created_at  timestamptz  not null default now(),
+    expires_at  timestamptz  not null,
+    primary key (user_id, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -8,11 +8,15 @@ import (
 	"backendForKeenEye/internal/usecases"
 	encryptionService "backendForKeenEye/pkg/encryption-service"
 	jwtService "backendForKeenEye/pkg/jwt-service"
+	"backendForKeenEye/pkg/notifier"
+	passwordPolicy "backendForKeenEye/pkg/password-policy"
 	"backendForKeenEye/pkg/postgres"
+	"backendForKeenEye/pkg/tabular"
 	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"log"
+	"time"
 )
 
 type Container struct {
@@ -21,15 +25,23 @@ type Container struct {
 
 	PGClient *postgres.Client
 
+	AuthController    controllers.AuthController
 	UserController    controllers.UserController
 	StudentController controllers.StudentController
 	TeacherController controllers.TeacherController
 	AdminController   controllers.AdminController
 	GroupController   controllers.GroupController
+	LockoutController controllers.LockoutController
+	RoleController    controllers.RoleController
+	AuditController   controllers.AuditController
+	TrashController   controllers.TrashController
+	SearchController  controllers.SearchController
+	ExportController  controllers.ExportController
 
-	AuthMiddleware         func() func(c *gin.Context)
-	AdminMiddleware        func() func(c *gin.Context)
-	TeacherAdminMiddleware func() func(c *gin.Context)
+	AuthMiddleware        func() func(c *gin.Context)
+	PermissionMiddleware  func(permission string) func(c *gin.Context)
+	DeprecationMiddleware func(successor string) func(c *gin.Context)
+	IdempotencyMiddleware func() func(c *gin.Context)
 }
 
 func NewContainer() *Container {
@@ -48,52 +60,195 @@ func NewContainer() *Container {
 	}
 
 	ctx := context.Background()
-	encryption := encryptionService.NewEncryptionService(cfg.Salt)
+	encryption := encryptionService.NewEncryptionService(encryptionService.Params{
+		Algorithm:         cfg.Algorithm,
+		BcryptCost:        cfg.BcryptCost,
+		Argon2Memory:      cfg.Memory,
+		Argon2Iterations:  cfg.Iterations,
+		Argon2Parallelism: cfg.Parallelism,
+		Argon2KeyLength:   cfg.KeyLength,
+		Argon2SaltLength:  cfg.Argon2.SaltLength,
+	})
 	jwt := jwtService.NewJWTService(cfg.Key, cfg.AccessTime, cfg.RefreshTime)
 
+	policy, err := passwordPolicy.NewPasswordPolicy(passwordPolicy.Config{
+		MinLength:        cfg.MinLength,
+		MaxLength:        cfg.MaxLength,
+		RequireLowercase: cfg.RequireLowercase,
+		RequireUppercase: cfg.RequireUppercase,
+		RequireDigit:     cfg.RequireDigit,
+		RequireSymbol:    cfg.RequireSymbol,
+		DenyListPath:     cfg.DenyListPath,
+	})
+	if err != nil {
+		log.Fatalf("failed to load password policy: %v", err)
+	}
+
 	studentRepo := repositories.NewStudentRepository(pgClient.Pool, pgClient.Builder)
 	userRepo := repositories.NewUserRepository(pgClient.Pool, pgClient.Builder)
 	teacherRepo := repositories.NewTeacherRepository(pgClient.Pool, pgClient.Builder)
 	adminRepo := repositories.NewAdminRepository(pgClient.Pool, pgClient.Builder)
 	groupRepo := repositories.NewGroupRepository(pgClient.Pool, pgClient.Builder)
+	refreshTokenRepo := repositories.NewRefreshTokenRepository(pgClient.Pool, pgClient.Builder)
+	revokedTokenRepo := repositories.NewRevokedTokenRepository(pgClient.Pool, pgClient.Builder)
+	passwordResetTokenRepo := repositories.NewPasswordResetTokenRepository(pgClient.Pool, pgClient.Builder)
+	roleRepo := repositories.NewRoleRepository(pgClient.Pool, pgClient.Builder)
+	auditRepo := repositories.NewAuditRepository(pgClient.Pool, pgClient.Builder)
+	trashRepo := repositories.NewTrashRepository(pgClient.Pool, pgClient.Builder)
+	searchRepo := repositories.NewSearchRepository(pgClient.Pool, pgClient.Builder)
+	idempotencyKeyRepo := repositories.NewIdempotencyKeyRepository(pgClient.Pool, pgClient.Builder)
+	transactor := repositories.NewTransactor(pgClient.Pool)
+
+	var notifications usecases.Notifier
+	switch cfg.Notifier.Type {
+	case "smtp":
+		notifications = notifier.NewSMTPNotifier(cfg.Notifier.SMTP, cfg.LinkFormat)
+	default:
+		notifications = notifier.NewLogNotifier(cfg.LogPath, cfg.LinkFormat)
+	}
+
+	denylist := usecases.NewTokenDenylist(revokedTokenRepo)
+	if err = denylist.Sync(ctx); err != nil {
+		fmt.Printf("failed to load token denylist: %v\n", err)
+	}
+	go denylist.Run(ctx, cfg.DenylistSyncInterval)
+
+	access := usecases.NewAccessControl(roleRepo, groupRepo)
+	if err = access.Sync(ctx); err != nil {
+		fmt.Printf("failed to load role permissions: %v\n", err)
+	}
+	go access.Run(ctx, cfg.Permissions.SyncInterval)
+
+	idempotency := usecases.NewIdempotency(idempotencyKeyRepo, cfg.Idempotency.TTL, cfg.Idempotency.LockTimeout)
+	go idempotency.Run(ctx, cfg.Idempotency.CleanupInterval)
+
+	var loginAttemptRepo usecases.LoginAttemptRepository
+	switch cfg.BruteForce.Store {
+	case "memory":
+		loginAttemptRepo = repositories.NewMemoryLoginAttemptRepository()
+	default:
+		loginAttemptRepo = repositories.NewLoginAttemptRepository(pgClient.Pool, pgClient.Builder)
+	}
+
+	throttler := usecases.NewLoginThrottler(loginAttemptRepo, usecases.LoginThrottlerConfig{
+		MaxLoginFailures: cfg.MaxLoginFailures,
+		MaxIpFailures:    cfg.MaxIpFailures,
+		FailureWindow:    cfg.FailureWindow,
+		LockoutDuration:  cfg.LockoutDuration,
+		BaseDelay:        cfg.BaseDelay,
+		MaxDelay:         cfg.MaxDelay,
+	})
+
+	// reset requests are counted per address on counters of their own: a
+	// budget per window, without delays between requests
+	resetThrottler := usecases.NewLoginThrottler(loginAttemptRepo, usecases.LoginThrottlerConfig{
+		Scope:           "password_reset:",
+		MaxIpFailures:   cfg.PasswordReset.IpLimit,
+		FailureWindow:   cfg.PasswordReset.IpWindow,
+		LockoutDuration: cfg.PasswordReset.IpWindow,
+	})
+
+	auditor := usecases.NewAuditor(auditRepo)
+
+	authService := usecases.NewAuthService(userRepo, studentRepo, teacherRepo, adminRepo, encryption, jwt, denylist, throttler)
+
+	login := usecases.NewLoginUsecase(authService, refreshTokenRepo, jwt)
+	refreshTokens := usecases.NewRefreshTokensUsecase(refreshTokenRepo, userRepo, jwt)
+	logout := usecases.NewLogoutUsecase(jwt, denylist, refreshTokenRepo)
+	revokeSessions := usecases.NewRevokeSessionsUsecase(userRepo, refreshTokenRepo, transactor, auditor)
+	changePassword := usecases.NewChangePasswordUsecase(userRepo, encryption, policy, transactor, auditor)
+	resetPassword := usecases.NewResetPasswordUsecase(userRepo, revokeSessions, encryption, encryption, transactor, auditor)
+	requestPasswordReset := usecases.NewRequestPasswordResetUsecase(userRepo, passwordResetTokenRepo, notifications, resetThrottler, cfg.TokenTTL)
+	confirmPasswordReset := usecases.NewConfirmPasswordResetUsecase(passwordResetTokenRepo, userRepo, revokeSessions, encryption, policy, transactor, auditor)
+
+	readLoginAttempts := usecases.NewReadLoginAttemptsUsecase(loginAttemptRepo, cfg.FailureWindow)
+	unlockLogin := usecases.NewUnlockLoginUsecase(loginAttemptRepo, transactor, auditor)
 
-	authService := usecases.NewAuthService(userRepo, studentRepo, teacherRepo, adminRepo, encryption, jwt)
+	readMe := usecases.NewReadMeUsecase(authService, groupRepo, teacherRepo)
+	updateMe := usecases.NewUpdateMeUsecase(userRepo, studentRepo, teacherRepo, adminRepo, readMe, transactor, auditor)
 
 	readAllStudents := usecases.NewReadAllStudentsUsecase(studentRepo)
 	readAllStudentsByGroupId := usecases.NewReadAllStudentsByGroupIdUsecase(studentRepo)
 	readStudent := usecases.NewReadStudentUsecase(studentRepo)
-	updateStudent := usecases.NewUpdateStudentUsecase(studentRepo)
-	deleteStudent := usecases.NewDeleteStudentUsecase(studentRepo)
+	updateStudent := usecases.NewUpdateStudentUsecase(studentRepo, access, transactor, auditor)
+	deleteStudent := usecases.NewDeleteStudentUsecase(studentRepo, transactor, auditor)
+	bulkAssignStudents := usecases.NewBulkAssignStudentsUsecase(studentRepo, groupRepo, access, transactor, auditor, cfg.Bulk.MaxItems)
+	bulkDeleteStudents := usecases.NewBulkDeleteStudentsUsecase(studentRepo, transactor, auditor, cfg.Bulk.MaxItems)
+	bulkRestoreStudents := usecases.NewBulkRestoreStudentsUsecase(trashRepo, transactor, auditor, cfg.Bulk.MaxItems)
 
-	createUser := usecases.NewCreateUserUsecase(userRepo, encryption, jwt)
+	createUser := usecases.NewCreateUserUsecase(userRepo, encryption, policy, transactor, auditor)
+	importUsers := usecases.NewImportUsersUsecase(userRepo, groupRepo, encryption, encryption, transactor, auditor)
 
 	readAllTeachers := usecases.NewReadAllTeachersUsecase(teacherRepo)
 	readTeacher := usecases.NewReadTeacherUsecase(teacherRepo)
-	updateTeacher := usecases.NewUpdateTeacherUsecase(teacherRepo)
-	deleteTeacher := usecases.NewDeleteTeacherUsecase(teacherRepo)
+	updateTeacher := usecases.NewUpdateTeacherUsecase(teacherRepo, transactor, auditor)
 
 	readAdmin := usecases.NewReadAdminUsecase(adminRepo)
-	updateAdmin := usecases.NewUpdateAdminUsecase(adminRepo)
-	deleteAdmin := usecases.NewDeleteAdminUsecase(adminRepo)
+	updateAdmin := usecases.NewUpdateAdminUsecase(adminRepo, transactor, auditor)
+	deleteAdmin := usecases.NewDeleteAdminUsecase(adminRepo, transactor, auditor)
 
-	createGroup := usecases.NewCreateGroupUsecase(groupRepo)
+	createGroup := usecases.NewCreateGroupUsecase(groupRepo, transactor, auditor)
 	readAllGroups := usecases.NewReadAllGroupsUsecase(groupRepo)
 	readGroup := usecases.NewReadGroupUsecase(groupRepo)
-	updateGroup := usecases.NewUpdateGroupUsecase(groupRepo)
-	deleteGroup := usecases.NewDeleteGroupUsecase(groupRepo)
+	updateGroup := usecases.NewUpdateGroupUsecase(groupRepo, transactor, auditor)
+	deleteGroup := usecases.NewDeleteGroupUsecase(groupRepo, studentRepo, transactor, auditor)
+	deleteTeacher := usecases.NewDeleteTeacherUsecase(teacherRepo, groupRepo, &deleteGroup, transactor, auditor)
 
-	accountController := controllers.NewUserController(&createUser)
+	readRoles := usecases.NewReadRolesUsecase(roleRepo)
+	createRole := usecases.NewCreateRoleUsecase(roleRepo, transactor, auditor)
+	updateRoleGrants := usecases.NewUpdateRoleGrantsUsecase(roleRepo, access, transactor, auditor)
+
+	readAuditLog := usecases.NewReadAuditLogUsecase(auditRepo)
+
+	search := usecases.NewSearchUsecase(searchRepo, access)
+
+	exportStudents := usecases.NewExportStudentsUsecase(studentRepo, groupRepo)
+	exportTeachers := usecases.NewExportTeachersUsecase(teacherRepo)
+	exportGroups := usecases.NewExportGroupsUsecase(groupRepo, teacherRepo)
+
+	exportFont, err := tabular.LoadFont(cfg.Export.FontPath)
+	if err != nil {
+		fmt.Printf("failed to load export font, PDF export is off: %v\n", err)
+	}
+
+	readDeleted := usecases.NewReadDeletedUsecase(trashRepo)
+	restoreDeleted := usecases.NewRestoreDeletedUsecase(trashRepo, transactor, auditor)
+	purgeDeleted := usecases.NewPurgeDeletedUsecase(trashRepo, transactor, auditor)
+
+	retention := usecases.NewTrashRetention(trashRepo, &purgeDeleted, cfg.DeletedTTL)
+	go retention.Run(ctx, cfg.PurgeInterval)
+
+	checkReferences := usecases.NewCheckReferencesUsecase(teacherRepo, groupRepo)
+	if err = controllers.RegisterValidators(&checkReferences); err != nil {
+		log.Fatalf("failed to register validators: %v", err)
+	}
+
+	deprecatedAt, err := time.Parse(time.DateOnly, cfg.DeprecatedAt)
+	if err != nil {
+		log.Fatalf("failed to parse legacy api deprecation date: %v", err)
+	}
+	sunset, err := time.Parse(time.DateOnly, cfg.Sunset)
+	if err != nil {
+		log.Fatalf("failed to parse legacy api sunset date: %v", err)
+	}
+
+	authController := controllers.NewAuthController(&login, &refreshTokens, &logout, &requestPasswordReset, &confirmPasswordReset)
+	accountController := controllers.NewUserController(&createUser, &revokeSessions, &changePassword, &resetPassword, &readMe, &updateMe, &importUsers)
 
 	studentController := controllers.NewStudentController(
-		&readGroup,
+		access,
 		&readAllStudents,
 		&readAllStudentsByGroupId,
 		&readStudent,
 		&updateStudent,
 		&deleteStudent,
+		&bulkAssignStudents,
+		&bulkDeleteStudents,
+		&bulkRestoreStudents,
 	)
 
 	teacherController := controllers.NewTeacherController(
+		access,
 		&readAllTeachers,
 		&readTeacher,
 		&updateTeacher,
@@ -101,12 +256,14 @@ func NewContainer() *Container {
 	)
 
 	adminController := controllers.NewAdminController(
+		access,
 		&readAdmin,
 		&updateAdmin,
 		&deleteAdmin,
 	)
 
 	groupController := controllers.NewGroupController(
+		access,
 		&createGroup,
 		&readAllGroups,
 		&readGroup,
@@ -114,17 +271,36 @@ func NewContainer() *Container {
 		&deleteGroup,
 	)
 
+	lockoutController := controllers.NewLockoutController(&readLoginAttempts, &unlockLogin)
+	roleController := controllers.NewRoleController(&readRoles, &createRole, &updateRoleGrants)
+	auditController := controllers.NewAuditController(&readAuditLog)
+	trashController := controllers.NewTrashController(&readDeleted, &restoreDeleted, &purgeDeleted)
+	searchController := controllers.NewSearchController(&search)
+	exportController := controllers.NewExportController(&exportStudents, &exportTeachers, &exportGroups, access, exportFont)
+
 	return &Container{
-		Cfg:                    *cfg,
-		Ctx:                    ctx,
-		PGClient:               pgClient,
-		UserController:         accountController,
-		StudentController:      studentController,
-		TeacherController:      teacherController,
-		AdminController:        adminController,
-		GroupController:        groupController,
-		AuthMiddleware:         func() func(c *gin.Context) { return middlewares.AuthMiddleware(ctx, authService) },
-		AdminMiddleware:        func() func(c *gin.Context) { return middlewares.AdminMiddleware() },
-		TeacherAdminMiddleware: func() func(c *gin.Context) { return middlewares.TeacherAdminMiddleware() },
+		Cfg:               *cfg,
+		Ctx:               ctx,
+		PGClient:          pgClient,
+		AuthController:    authController,
+		UserController:    accountController,
+		StudentController: studentController,
+		TeacherController: teacherController,
+		AdminController:   adminController,
+		GroupController:   groupController,
+		LockoutController: lockoutController,
+		RoleController:    roleController,
+		AuditController:   auditController,
+		TrashController:   trashController,
+		SearchController:  searchController,
+		ExportController:  exportController,
+		AuthMiddleware:    func() func(c *gin.Context) { return middlewares.AuthMiddleware(ctx, authService) },
+		PermissionMiddleware: func(permission string) func(c *gin.Context) {
+			return middlewares.PermissionMiddleware(access, permission)
+		},
+		DeprecationMiddleware: func(successor string) func(c *gin.Context) {
+			return middlewares.DeprecationMiddleware(deprecatedAt, sunset, successor)
+		},
+		IdempotencyMiddleware: func() func(c *gin.Context) { return middlewares.IdempotencyMiddleware(idempotency) },
 	}
 }
@@ -2,123 +2,184 @@ package controllers
 
 import (
 	"backendForKeenEye/internal/controllers/requests"
+	"backendForKeenEye/internal/entities"
 	"backendForKeenEye/internal/usecases"
-	"fmt"
+	"backendForKeenEye/pkg/patch"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
 )
 
 type AdminController struct {
+	access             AccessController
 	readAdminUsecase   ReadAdminUsecase
 	updateAdminUsecase UpdateAdminUsecase
 	deleteAdminUsecase DeleteAdminUsecase
 }
 
-func NewAdminController(readAdminUsecase ReadAdminUsecase, updateAdminUsecase UpdateAdminUsecase, deleteAdminUsecase DeleteAdminUsecase) AdminController {
-	return AdminController{readAdminUsecase: readAdminUsecase, updateAdminUsecase: updateAdminUsecase, deleteAdminUsecase: deleteAdminUsecase}
+func NewAdminController(access AccessController, readAdminUsecase ReadAdminUsecase, updateAdminUsecase UpdateAdminUsecase, deleteAdminUsecase DeleteAdminUsecase) AdminController {
+	return AdminController{access: access, readAdminUsecase: readAdminUsecase, updateAdminUsecase: updateAdminUsecase, deleteAdminUsecase: deleteAdminUsecase}
 }
 
 // ReadAdmin
 // @Summary      Get admin by ID
-// @Description  Get admin by ID (admin only)
+// @Description  Get admin by ID (requires admins.read)
 // @Tags         admins
 // @Security     BasicAuth
 // @Produce      json
-// @Param        id query int true "Admin ID"
+// @Param        id path int true "Admin ID"
+// @Param        include_deleted query bool false "Also return a deleted admin (requires trash.manage)"
+// @Param        If-None-Match header string false "ETag of a cached copy; 304 is returned if it is still current"
 // @Success      200 {object} usecases.ReadAdminResponseDto
+// @Header       200 {string} ETag "Version of the representation"
+// @Success      304 "Not modified"
 // @Failure      400 {object} object "Invalid admin ID"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Admin not found"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/read-admin [get]
+// @Router       /api/v1/admins/{id} [get]
 func (controller *AdminController) ReadAdmin(c *gin.Context) {
-	idStr := c.Query("id")
-	if idStr == "" {
-		c.AbortWithStatus(http.StatusBadRequest)
+	id, ok := idParam(c)
+	if !ok {
 		return
 	}
 
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+	includeDeleted, _ := strconv.ParseBool(c.Query("include_deleted"))
+	if includeDeleted && !authorize(c, controller.access, entities.TrashManagePermission, usecases.Resource{}) {
 		return
 	}
 
-	data, err := controller.readAdminUsecase.ReadAdmin(c, usecases.ReadAdminRequestDto{Id: int(id)})
+	data, err := controller.readAdminUsecase.ReadAdmin(c, usecases.ReadAdminRequestDto{Id: id, IncludeDeleted: includeDeleted})
 	if err != nil {
-		fmt.Println("failed to read admin:", err)
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, data)
+	respondWithTag(c, entityTag(data.Admin.Version), data)
 }
 
 // UpdateAdmin
 // @Summary      Update admin
-// @Description  Update admin info (admin only)
+// @Description  Update admin info (requires admins.update)
 // @Tags         admins
 // @Security     BasicAuth
 // @Accept       json
 // @Produce      json
 // @Param        admin body requests.UpdateAdminRequest true "Updated admin info"
+// @Param        If-Match header string false "ETag of the representation the change is based on, or *; any version if omitted"
 // @Success      200 {object} entities.Admin
 // @Failure      400 {object} object "Invalid request"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Admin not found"
+// @Failure      412 {object} object "Entity was changed since it was read"
 // @Failure      500 {object} object "Internal server error"
+// @Deprecated
 // @Router       /api/update-admin [put]
 func (controller *AdminController) UpdateAdmin(c *gin.Context) {
 	req := requests.UpdateAdminRequest{}
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	versions, ok := ifMatch(c, 1)
+	if !ok {
+		return
+	}
+
+	data, err := controller.updateAdminUsecase.UpdateAdmin(c, usecases.UpdateAdminRequestDto{Id: req.Id, Version: versions[0], Fio: patch.NonZero(req.Fio), PhoneNumber: patch.NonZero(req.PhoneNumber)})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", entityTag(data.Admin.Version))
+	c.JSON(http.StatusOK, data)
+}
+
+// PatchAdmin
+// @Summary      Patch admin
+// @Description  Apply a JSON merge patch (RFC 7396) to an admin record: absent fields are left unchanged and null clears a field. fio can't be cleared. Requires admins.update.
+// @Tags         admins
+// @Security     BasicAuth
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path int true "Admin ID"
+// @Param        admin body requests.PatchAdminRequest true "Changed fields"
+// @Param        If-Match header string true "ETag of the representation the change is based on, or *"
+// @Success      200 {object} entities.Admin
+// @Failure      400 {object} object "Invalid request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Admin not found"
+// @Failure      412 {object} object "Entity was changed since it was read"
+// @Failure      428 {object} object "If-Match header is missing"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/admins/{id} [patch]
+func (controller *AdminController) PatchAdmin(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+
+	req := requests.PatchAdminRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
 		return
 	}
 
-	data, err := controller.updateAdminUsecase.UpdateAdmin(c, usecases.UpdateAdminRequestDto{Id: req.Id, Fio: req.Fio, PhoneNumber: req.PhoneNumber})
+	versions, ok := ifMatch(c, 1)
+	if !ok {
+		return
+	}
+
+	data, err := controller.updateAdminUsecase.UpdateAdmin(c, usecases.UpdateAdminRequestDto{Id: id, Version: versions[0], Fio: req.Fio, PhoneNumber: req.PhoneNumber})
 	if err != nil {
-		fmt.Println("failed to update admin")
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
+	c.Header("ETag", entityTag(data.Admin.Version))
 	c.JSON(http.StatusOK, data)
 }
 
 // DeleteAdmin
 // @Summary      Delete admin
-// @Description  Delete admin by ID (admin only)
+// @Description  Delete admin by ID (requires admins.delete)
 // @Tags         admins
 // @Security     BasicAuth
 // @Produce      json
-// @Param        id query int true "Admin ID"
-// @Success      200
+// @Param        id path int true "Admin ID"
+// @Param        If-Match header string true "ETag of the representation the change is based on, or *"
+// @Success      204
 // @Failure      400 {object} object "Invalid admin ID"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Admin not found"
+// @Failure      412 {object} object "Entity was changed since it was read"
+// @Failure      428 {object} object "If-Match header is missing"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/delete-admin [delete]
+// @Router       /api/v1/admins/{id} [delete]
 func (controller *AdminController) DeleteAdmin(c *gin.Context) {
-	idStr := c.Query("id")
-	if idStr == "" {
-		c.AbortWithStatus(http.StatusBadRequest)
+	id, ok := idParam(c)
+	if !ok {
 		return
 	}
 
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+	versions, ok := ifMatch(c, 1)
+	if !ok {
 		return
 	}
 
-	err = controller.deleteAdminUsecase.DeleteAdmin(c, usecases.DeleteAdminRequestDto{Id: int(id)})
+	err := controller.deleteAdminUsecase.DeleteAdmin(c, usecases.DeleteAdminRequestDto{Id: id, Version: versions[0]})
 	if err != nil {
-		fmt.Println("failed to delete admin:", err)
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.AbortWithStatus(http.StatusOK)
+	respondDeleted(c)
 }
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"backendForKeenEye/internal/usecases"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AuditController struct {
+	readAuditLogUsecase ReadAuditLogUsecase
+}
+
+func NewAuditController(readAuditLogUsecase ReadAuditLogUsecase) AuditController {
+	return AuditController{readAuditLogUsecase: readAuditLogUsecase}
+}
+
+// ReadAuditLog
+// @Summary      Get audit log
+// @Description  Recorded changes, newest first (requires audit.read)
+// @Tags         audit
+// @Security     BasicAuth
+// @Produce      json
+// @Param        actor_id query int false "Id of the user who made the change"
+// @Param        entity_type query string false "Entity type (user, student, teacher, admin, group, role, login_attempt)"
+// @Param        entity_id query string false "Entity id"
+// @Param        action query string false "Action (create, update, delete, change_password, reset_password, revoke_sessions, unlock, update_grants, restore, purge)"
+// @Param        from query string false "Start of the time range, RFC 3339, inclusive"
+// @Param        to query string false "End of the time range, RFC 3339, exclusive"
+// @Param        limit query int false "Maximum number of entries (default 100, at most 1000)"
+// @Param        offset query int false "Number of entries to skip"
+// @Success      200 {object} usecases.ReadAuditLogResponseDto
+// @Failure      400 {object} object "Invalid filter"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/audit [get]
+func (controller *AuditController) ReadAuditLog(c *gin.Context) {
+	request := usecases.ReadAuditLogRequestDto{
+		EntityType: c.Query("entity_type"),
+		EntityId:   c.Query("entity_id"),
+		Action:     c.Query("action"),
+	}
+
+	var err error
+	for param, target := range map[string]*int{"actor_id": &request.ActorId, "limit": &request.Limit, "offset": &request.Offset} {
+		if value := c.Query(param); value != "" {
+			if *target, err = strconv.Atoi(value); err != nil {
+				abortWithError(c, paramError(param, "must be an integer"))
+				return
+			}
+		}
+	}
+	for param, target := range map[string]*time.Time{"from": &request.From, "to": &request.To} {
+		if value := c.Query(param); value != "" {
+			if *target, err = time.Parse(time.RFC3339, value); err != nil {
+				abortWithError(c, paramError(param, "must be an RFC 3339 timestamp"))
+				return
+			}
+		}
+	}
+
+	data, err := controller.readAuditLogUsecase.ReadAuditLog(c, request)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"backendForKeenEye/internal/controllers/requests"
+	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/internal/usecases"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+var bearerRequiredError = entities.NewDomainError(entities.ValidationCode, "logout requires a Bearer token")
+
+type AuthController struct {
+	loginUsecase                LoginUsecase
+	refreshTokensUsecase        RefreshTokensUsecase
+	logoutUsecase               LogoutUsecase
+	requestPasswordResetUsecase RequestPasswordResetUsecase
+	confirmPasswordResetUsecase ConfirmPasswordResetUsecase
+}
+
+func NewAuthController(loginUsecase LoginUsecase, refreshTokensUsecase RefreshTokensUsecase, logoutUsecase LogoutUsecase, requestPasswordResetUsecase RequestPasswordResetUsecase, confirmPasswordResetUsecase ConfirmPasswordResetUsecase) AuthController {
+	return AuthController{loginUsecase: loginUsecase, refreshTokensUsecase: refreshTokensUsecase, logoutUsecase: logoutUsecase, requestPasswordResetUsecase: requestPasswordResetUsecase, confirmPasswordResetUsecase: confirmPasswordResetUsecase}
+}
+
+// Login
+// @Summary      Log in
+// @Description  Exchange login and password for an access/refresh token pair together with the user's role and profile
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials body requests.LoginRequest true "Login and password"
+// @Success      200 {object} usecases.LoginResponseDto
+// @Failure      400 {object} object "Invalid request"
+// @Failure      401 {object} object "Invalid login or password"
+// @Failure      429 {object} object "Too many failed attempts"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/login [post]
+func (controller *AuthController) Login(c *gin.Context) {
+	req := requests.LoginRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	data, err := controller.loginUsecase.Login(c, usecases.LoginRequestDto{Login: req.Login, Password: req.Password, Ip: c.ClientIP()})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// RefreshTokens
+// @Summary      Refresh tokens
+// @Description  Exchange a refresh token for a new access/refresh pair. Every refresh token is single-use; presenting an already used one revokes the whole session.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        token body requests.RefreshTokensRequest true "Refresh token"
+// @Success      200 {object} usecases.RefreshTokensResponseDto
+// @Failure      400 {object} object "Invalid request"
+// @Failure      401 {object} object "Invalid or reused refresh token"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/refresh [post]
+func (controller *AuthController) RefreshTokens(c *gin.Context) {
+	req := requests.RefreshTokensRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	data, err := controller.refreshTokensUsecase.RefreshTokens(c, usecases.RefreshTokensRequestDto{RefreshToken: req.RefreshToken})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// Logout
+// @Summary      Log out
+// @Description  End the current session: the access token is revoked immediately and the refresh tokens of its session can no longer be used
+// @Tags         auth
+// @Security     BearerAuth
+// @Success      204
+// @Failure      400 {object} object "Session is not token based"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/logout [post]
+func (controller *AuthController) Logout(c *gin.Context) {
+	authHeader := c.GetHeader("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		abortWithError(c, bearerRequiredError)
+		return
+	}
+
+	err := controller.logoutUsecase.Logout(c, usecases.LogoutRequestDto{AccessToken: strings.TrimPrefix(authHeader, "Bearer ")})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+// RequestPasswordReset
+// @Summary      Request password reset
+// @Description  Send a single-use password reset token to the user. The response is the same whether the login exists or not.
+// @Tags         auth
+// @Accept       json
+// @Param        login body requests.RequestPasswordResetRequest true "Login"
+// @Success      202
+// @Failure      400 {object} object "Invalid request"
+// @Failure      429 {object} object "Too many requests from the address"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/password-reset/request [post]
+func (controller *AuthController) RequestPasswordReset(c *gin.Context) {
+	req := requests.RequestPasswordResetRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	err = controller.requestPasswordResetUsecase.RequestPasswordReset(c, usecases.RequestPasswordResetRequestDto{Login: req.Login, Ip: c.ClientIP()})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.AbortWithStatus(http.StatusAccepted)
+}
+
+// ConfirmPasswordReset
+// @Summary      Confirm password reset
+// @Description  Set a new password using a reset token. The token can be used only once.
+// @Tags         auth
+// @Accept       json
+// @Param        reset body requests.ConfirmPasswordResetRequest true "Reset token and new password"
+// @Success      204
+// @Failure      400 {object} object "Invalid request or expired token"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/password-reset/confirm [post]
+func (controller *AuthController) ConfirmPasswordReset(c *gin.Context) {
+	req := requests.ConfirmPasswordResetRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	err = controller.confirmPasswordResetUsecase.ConfirmPasswordReset(c, usecases.ConfirmPasswordResetRequestDto{Token: req.Token, NewPassword: req.NewPassword})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.AbortWithStatus(http.StatusNoContent)
+}
@@ -1,15 +1,69 @@
 package controllers
 
 import (
+	"backendForKeenEye/internal/entities"
 	"backendForKeenEye/internal/usecases"
 	"context"
 )
 
+type LoginUsecase interface {
+	Login(context.Context, usecases.LoginRequestDto) (usecases.LoginResponseDto, error)
+}
+
+type RefreshTokensUsecase interface {
+	RefreshTokens(context.Context, usecases.RefreshTokensRequestDto) (usecases.RefreshTokensResponseDto, error)
+}
+
+type LogoutUsecase interface {
+	Logout(context.Context, usecases.LogoutRequestDto) error
+}
+
+type RevokeSessionsUsecase interface {
+	RevokeSessions(context.Context, usecases.RevokeSessionsRequestDto) error
+}
+
+type ChangePasswordUsecase interface {
+	ChangePassword(context.Context, usecases.ChangePasswordRequestDto) error
+}
+
+type ResetPasswordUsecase interface {
+	ResetPassword(context.Context, usecases.ResetPasswordRequestDto) (usecases.ResetPasswordResponseDto, error)
+}
+
+type RequestPasswordResetUsecase interface {
+	RequestPasswordReset(context.Context, usecases.RequestPasswordResetRequestDto) error
+}
+
+type ConfirmPasswordResetUsecase interface {
+	ConfirmPasswordReset(context.Context, usecases.ConfirmPasswordResetRequestDto) error
+}
+
+type ReadLoginAttemptsUsecase interface {
+	ReadLoginAttempts(context.Context) (usecases.ReadLoginAttemptsResponseDto, error)
+}
+
+type UnlockLoginUsecase interface {
+	UnlockLogin(context.Context, usecases.UnlockLoginRequestDto) error
+}
+
+type ReadMeUsecase interface {
+	ReadMe(context.Context, usecases.ReadMeRequestDto) (usecases.ReadMeResponseDto, error)
+}
+
+type UpdateMeUsecase interface {
+	UpdateMe(context.Context, usecases.UpdateMeRequestDto) (usecases.ReadMeResponseDto, error)
+}
+
 type CreateUserUsecase interface {
 	CreateUser(context.Context, usecases.CreateUserRequestDto) (usecases.CreateUserResponseDto, error)
 }
+
+type ImportUsersUsecase interface {
+	ImportUsers(context.Context, usecases.ImportUsersRequestDto) (usecases.ImportUsersResponseDto, error)
+}
+
 type ReadAllStudentsUsecase interface {
-	ReadAllStudents(context.Context) (usecases.ReadAllStudentsResponseDto, error)
+	ReadAllStudents(context.Context, usecases.ReadAllStudentsRequestDto) (usecases.ReadAllStudentsResponseDto, error)
 }
 
 type ReadAllStudentsByGroupIdUsecase interface {
@@ -28,8 +82,20 @@ type DeleteStudentUsecase interface {
 	DeleteStudent(context.Context, usecases.DeleteStudentRequestDto) error
 }
 
+type BulkAssignStudentsUsecase interface {
+	BulkAssignStudents(context.Context, usecases.BulkAssignStudentsRequestDto) (usecases.BulkResponseDto, error)
+}
+
+type BulkDeleteStudentsUsecase interface {
+	BulkDeleteStudents(context.Context, usecases.BulkDeleteStudentsRequestDto) (usecases.BulkResponseDto, error)
+}
+
+type BulkRestoreStudentsUsecase interface {
+	BulkRestoreStudents(context.Context, usecases.BulkRestoreStudentsRequestDto) (usecases.BulkResponseDto, error)
+}
+
 type ReadAllTeachersUsecase interface {
-	ReadAllTeachers(context.Context) (usecases.ReadAllTeachersResponseDto, error)
+	ReadAllTeachers(context.Context, usecases.ReadAllTeachersRequestDto) (usecases.ReadAllTeachersResponseDto, error)
 }
 
 type ReadTeacherUsecase interface {
@@ -61,7 +127,7 @@ type CreateGroupUsecase interface {
 }
 
 type ReadAllGroupsUsecase interface {
-	ReadAllGroups(context.Context) (usecases.ReadAllGroupsResponseDto, error)
+	ReadAllGroups(context.Context, usecases.ReadAllGroupsRequestDto) (usecases.ReadAllGroupsResponseDto, error)
 }
 
 type ReadGroupUsecase interface {
@@ -75,3 +141,56 @@ type UpdateGroupUsecase interface {
 type DeleteGroupUsecase interface {
 	DeleteGroup(context.Context, usecases.DeleteGroupRequestDto) error
 }
+
+type AccessController interface {
+	Authorize(ctx context.Context, user entities.User, permission string, resource usecases.Resource) error
+}
+
+type ReadRolesUsecase interface {
+	ReadRoles(context.Context) (usecases.ReadRolesResponseDto, error)
+}
+
+type CreateRoleUsecase interface {
+	CreateRole(context.Context, usecases.CreateRoleRequestDto) (entities.Role, error)
+}
+
+type UpdateRoleGrantsUsecase interface {
+	UpdateRoleGrants(context.Context, usecases.UpdateRoleGrantsRequestDto) error
+}
+
+type ReadAuditLogUsecase interface {
+	ReadAuditLog(context.Context, usecases.ReadAuditLogRequestDto) (usecases.ReadAuditLogResponseDto, error)
+}
+
+type ReadDeletedUsecase interface {
+	ReadDeleted(context.Context, usecases.ReadDeletedRequestDto) (usecases.ReadDeletedResponseDto, error)
+}
+
+type RestoreDeletedUsecase interface {
+	RestoreDeleted(context.Context, usecases.RestoreDeletedRequestDto) error
+}
+
+type PurgeDeletedUsecase interface {
+	PurgeDeleted(context.Context, usecases.PurgeDeletedRequestDto) error
+}
+
+type SearchUsecase interface {
+	Search(context.Context, usecases.SearchRequestDto) (usecases.SearchResponseDto, error)
+}
+
+type ExportStudentsUsecase interface {
+	ExportStudents(context.Context, usecases.ExportStudentsRequestDto) (usecases.Export, error)
+}
+
+type ExportTeachersUsecase interface {
+	ExportTeachers(context.Context, usecases.ExportTeachersRequestDto) (usecases.Export, error)
+}
+
+type ExportGroupsUsecase interface {
+	ExportGroups(context.Context, usecases.ExportGroupsRequestDto) (usecases.Export, error)
+}
+
+type ReferenceChecker interface {
+	TeacherExists(ctx context.Context, id int) (bool, error)
+	GroupExists(ctx context.Context, id int) (bool, error)
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/internal/usecases"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var preconditionRequiredError = entities.NewDomainError(entities.PreconditionRequiredCode, "If-Match header is required")
+
+// entityTag formats the versions of the records making up a representation
+// as a strong ETag, e.g. "3" or "3.7".
+func entityTag(versions ...int) string {
+	parts := make([]string, len(versions))
+	for i, version := range versions {
+		parts[i] = strconv.Itoa(version)
+	}
+	return `"` + strings.Join(parts, ".") + `"`
+}
+
+// pageTag builds a weak ETag for a page of a list from the ids and versions
+// of its records, the cursor of the next page and the total count, if
+// requested, so it changes when a record is added, removed or changed.
+func pageTag[T any](records []T, key func(T) (id int, version int), next string, total *int) string {
+	hash := fnv.New64a()
+	for _, record := range records {
+		id, version := key(record)
+		_, _ = fmt.Fprintf(hash, "%d:%d;", id, version)
+	}
+	if next != "" {
+		_, _ = fmt.Fprintf(hash, "next:%s;", next)
+	}
+	if total != nil {
+		_, _ = fmt.Fprintf(hash, "total:%d;", *total)
+	}
+	return fmt.Sprintf(`W/"%x"`, hash.Sum64())
+}
+
+// respondWithTag sends data with its ETag, or 304 Not Modified if the client
+// sent the same tag in If-None-Match.
+func respondWithTag(c *gin.Context, tag string, data any) {
+	c.Header("ETag", tag)
+	if tagListed(c.GetHeader("If-None-Match"), tag) {
+		c.AbortWithStatus(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// tagListed compares the tags of an If-None-Match header with tag using the
+// weak comparison.
+func tagListed(header, tag string) bool {
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, listed := range strings.Split(header, ",") {
+		listed = strings.TrimSpace(listed)
+		if listed == "*" || strings.TrimPrefix(listed, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatch returns the versions named by the If-Match header, which requests
+// changing an entity must send. "*" accepts any version and yields zeros; so
+// does a missing header on the legacy routes, whose clients predate it. If
+// the header is missing or can't match an entity tag of count versions the
+// request is aborted and false is returned.
+func ifMatch(c *gin.Context, count int) ([]int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" && !c.GetBool("legacy_route") {
+		abortWithError(c, preconditionRequiredError)
+		return nil, false
+	}
+
+	versions := make([]int, count)
+	if header == "" || header == "*" {
+		return versions, true
+	}
+
+	parts := strings.Split(strings.Trim(header, `"`), ".")
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' || len(parts) != count {
+		abortWithError(c, usecases.VersionMismatchError)
+		return nil, false
+	}
+	for i, part := range parts {
+		version, err := strconv.Atoi(part)
+		if err != nil || version <= 0 {
+			abortWithError(c, usecases.VersionMismatchError)
+			return nil, false
+		}
+		versions[i] = version
+	}
+
+	return versions, true
+}
+
+func studentKey(student entities.Student) (int, int) {
+	return student.Id, student.Version
+}
+
+func teacherKey(teacher entities.Teacher) (int, int) {
+	return teacher.Id, teacher.Version
+}
+
+func groupKey(group entities.Group) (int, int) {
+	return group.Id, group.Version
+}
@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"backendForKeenEye/internal/controllers/requests"
+	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/internal/usecases"
+	"backendForKeenEye/pkg/tabular"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var notAcceptableError = entities.NewDomainError(entities.NotAcceptableCode, "export is available as text/csv, XLSX or application/pdf")
+
+type ExportController struct {
+	exportStudentsUsecase ExportStudentsUsecase
+	exportTeachersUsecase ExportTeachersUsecase
+	exportGroupsUsecase   ExportGroupsUsecase
+	access                AccessController
+	font                  *tabular.Font
+}
+
+// NewExportController takes the font to set PDF files in; without one only
+// CSV and XLSX are offered.
+func NewExportController(exportStudentsUsecase ExportStudentsUsecase, exportTeachersUsecase ExportTeachersUsecase, exportGroupsUsecase ExportGroupsUsecase, access AccessController, font *tabular.Font) ExportController {
+	return ExportController{exportStudentsUsecase: exportStudentsUsecase, exportTeachersUsecase: exportTeachersUsecase, exportGroupsUsecase: exportGroupsUsecase, access: access, font: font}
+}
+
+// ExportStudents
+// @Summary      Export students
+// @Description  Download the students as CSV (UTF-8 with BOM), XLSX or PDF, chosen by the Accept header or the format parameter (requires students.read). Columns: id, fio, phone_number, group_id, group. Filters and order as in the list.
+// @Tags         students
+// @Security     BasicAuth
+// @Produce      text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param        format query string false "csv, xlsx or pdf; overrides Accept"
+// @Param        columns query string false "Comma separated columns, all by default"
+// @Param        sort query string false "id (default), fio or group_id"
+// @Param        order query string false "asc (default) or desc"
+// @Param        fio query string false "Part of the fio"
+// @Param        group_id query int false "Group ID"
+// @Param        teacher_id query int false "Teacher of the group"
+// @Param        no_group query bool false "Only students without a group"
+// @Success      200 {file} file
+// @Header       200 {string} Content-Disposition "attachment; filename=students.csv"
+// @Failure      400 {object} object "Invalid parameters"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      406 {object} object "Format not available"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/students/export [get]
+func (controller *ExportController) ExportStudents(c *gin.Context) {
+	req, format, ok := controller.exportRequest(c)
+	if !ok {
+		return
+	}
+
+	data, err := controller.exportStudentsUsecase.ExportStudents(c, usecases.ExportStudentsRequestDto{ExportRequest: req})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	controller.respondWithExport(c, data, format, "students")
+}
+
+// ExportGroupStudents
+// @Summary      Export group roster
+// @Description  Download the students of a group as CSV (UTF-8 with BOM), XLSX or PDF, chosen by the Accept header or the format parameter. Requires groups.students.read for the group (by default its teacher and admins). Columns: id, fio, phone_number, group_id, group.
+// @Tags         groups
+// @Security     BasicAuth
+// @Produce      text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param        id path int true "Group ID"
+// @Param        format query string false "csv, xlsx or pdf; overrides Accept"
+// @Param        columns query string false "Comma separated columns, all by default"
+// @Param        sort query string false "id (default), fio or group_id"
+// @Param        order query string false "asc (default) or desc"
+// @Param        fio query string false "Part of the fio"
+// @Success      200 {file} file
+// @Header       200 {string} Content-Disposition "attachment; filename=group-1-students.csv"
+// @Failure      400 {object} object "Invalid parameters"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Group not found"
+// @Failure      406 {object} object "Format not available"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/groups/{id}/students/export [get]
+func (controller *ExportController) ExportGroupStudents(c *gin.Context) {
+	groupId, ok := idParam(c)
+	if !ok {
+		return
+	}
+
+	if !authorize(c, controller.access, entities.GroupStudentsReadPermission, usecases.Resource{GroupId: groupId}) {
+		return
+	}
+
+	req, format, ok := controller.exportRequest(c)
+	if !ok {
+		return
+	}
+
+	data, err := controller.exportStudentsUsecase.ExportStudents(c, usecases.ExportStudentsRequestDto{GroupId: groupId, ExportRequest: req})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	controller.respondWithExport(c, data, format, "group-"+strconv.Itoa(groupId)+"-students")
+}
+
+// ExportTeachers
+// @Summary      Export teachers
+// @Description  Download the teachers as CSV (UTF-8 with BOM), XLSX or PDF, chosen by the Accept header or the format parameter (requires teachers.read). Columns: id, fio, phone_number.
+// @Tags         teachers
+// @Security     BasicAuth
+// @Produce      text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param        format query string false "csv, xlsx or pdf; overrides Accept"
+// @Param        columns query string false "Comma separated columns, all by default"
+// @Param        sort query string false "id (default) or fio"
+// @Param        order query string false "asc (default) or desc"
+// @Param        fio query string false "Part of the fio"
+// @Param        no_group query bool false "Only teachers without a group"
+// @Success      200 {file} file
+// @Header       200 {string} Content-Disposition "attachment; filename=teachers.csv"
+// @Failure      400 {object} object "Invalid parameters"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      406 {object} object "Format not available"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/teachers/export [get]
+func (controller *ExportController) ExportTeachers(c *gin.Context) {
+	req, format, ok := controller.exportRequest(c)
+	if !ok {
+		return
+	}
+
+	data, err := controller.exportTeachersUsecase.ExportTeachers(c, usecases.ExportTeachersRequestDto{ExportRequest: req})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	controller.respondWithExport(c, data, format, "teachers")
+}
+
+// ExportGroups
+// @Summary      Export groups
+// @Description  Download the groups as CSV (UTF-8 with BOM), XLSX or PDF, chosen by the Accept header or the format parameter (requires groups.read). Columns: id, name, teacher_id, teacher.
+// @Tags         groups
+// @Security     BasicAuth
+// @Produce      text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param        format query string false "csv, xlsx or pdf; overrides Accept"
+// @Param        columns query string false "Comma separated columns, all by default"
+// @Param        sort query string false "id (default), name or teacher_id"
+// @Param        order query string false "asc (default) or desc"
+// @Param        teacher_id query int false "Teacher ID"
+// @Success      200 {file} file
+// @Header       200 {string} Content-Disposition "attachment; filename=groups.csv"
+// @Failure      400 {object} object "Invalid parameters"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      406 {object} object "Format not available"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/groups/export [get]
+func (controller *ExportController) ExportGroups(c *gin.Context) {
+	req, format, ok := controller.exportRequest(c)
+	if !ok {
+		return
+	}
+
+	data, err := controller.exportGroupsUsecase.ExportGroups(c, usecases.ExportGroupsRequestDto{ExportRequest: req})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	controller.respondWithExport(c, data, format, "groups")
+}
+
+// exportRequest binds the query parameters of an export and picks its
+// format. If they are invalid or no format is acceptable the request is
+// aborted and false is returned.
+func (controller *ExportController) exportRequest(c *gin.Context) (usecases.ExportRequest, tabular.Format, bool) {
+	req := requests.ExportRequest{}
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return usecases.ExportRequest{}, "", false
+	}
+
+	formats := []tabular.Format{tabular.CSV, tabular.XLSX}
+	if controller.font != nil {
+		formats = append(formats, tabular.PDF)
+	}
+
+	var format tabular.Format
+	if req.Format != "" {
+		for _, offered := range formats {
+			if string(offered) == req.Format {
+				format = offered
+			}
+		}
+	} else {
+		offers := make([]string, len(formats))
+		for i, offered := range formats {
+			offers[i], _, _ = strings.Cut(offered.ContentType(), ";")
+		}
+		accepted := c.NegotiateFormat(offers...)
+		for i, offer := range offers {
+			if offer == accepted {
+				format = formats[i]
+			}
+		}
+	}
+	if format == "" {
+		abortWithError(c, notAcceptableError)
+		return usecases.ExportRequest{}, "", false
+	}
+
+	var columns []string
+	for _, column := range strings.Split(req.Columns, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+
+	return usecases.ExportRequest{
+		Sort:    req.Sort,
+		Desc:    req.Order == "desc",
+		Filter:  entities.ListFilter{Fio: req.Fio, GroupId: req.GroupId, TeacherId: req.TeacherId, NoGroup: req.NoGroup},
+		Columns: columns,
+	}, format, true
+}
+
+// respondWithExport streams the export as an attachment. Once the first
+// bytes are out a failure can only cut the file short; it is logged.
+func (controller *ExportController) respondWithExport(c *gin.Context, data usecases.Export, format tabular.Format, name string) {
+	w, err := tabular.NewWriter(c.Writer, format, tabular.Options{Title: data.Title, Font: controller.font})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="`+name+"."+string(format)+`"`)
+	c.Status(http.StatusOK)
+
+	err = data.Rows(c, w.Write)
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+		}
+		abortWithError(c, err)
+	}
+}
@@ -4,13 +4,14 @@ import (
 	"backendForKeenEye/internal/controllers/requests"
 	"backendForKeenEye/internal/entities"
 	"backendForKeenEye/internal/usecases"
-	"fmt"
+	"backendForKeenEye/pkg/patch"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
 )
 
 type GroupController struct {
+	access               AccessController
 	createGroupUsecase   CreateGroupUsecase
 	readAllGroupsUsecase ReadAllGroupsUsecase
 	readGroupUsecase     ReadGroupUsecase
@@ -18,200 +19,249 @@ type GroupController struct {
 	deleteGroupUsecase   DeleteGroupUsecase
 }
 
-func NewGroupController(createGroupUsecase CreateGroupUsecase, readAllGroupsUsecase ReadAllGroupsUsecase, readGroupUsecase ReadGroupUsecase, updateGroupUsecase UpdateGroupUsecase, deleteGroupUsecase DeleteGroupUsecase) GroupController {
-	return GroupController{createGroupUsecase: createGroupUsecase, readAllGroupsUsecase: readAllGroupsUsecase, readGroupUsecase: readGroupUsecase, updateGroupUsecase: updateGroupUsecase, deleteGroupUsecase: deleteGroupUsecase}
+func NewGroupController(access AccessController, createGroupUsecase CreateGroupUsecase, readAllGroupsUsecase ReadAllGroupsUsecase, readGroupUsecase ReadGroupUsecase, updateGroupUsecase UpdateGroupUsecase, deleteGroupUsecase DeleteGroupUsecase) GroupController {
+	return GroupController{access: access, createGroupUsecase: createGroupUsecase, readAllGroupsUsecase: readAllGroupsUsecase, readGroupUsecase: readGroupUsecase, updateGroupUsecase: updateGroupUsecase, deleteGroupUsecase: deleteGroupUsecase}
 }
 
 // CreateGroup
 // @Summary      Create group
-// @Description  Create a new group (admin only)
+// @Description  Create a new group (requires groups.create)
 // @Tags         groups
 // @Security     BasicAuth
 // @Accept       json
 // @Produce      json
 // @Param        group body requests.CreateGroupRequest true "Group info"
 // @Success      201 {object} entities.Group
+// @Header       201 {string} Location "URL of the created group"
 // @Failure      400 {object} object "Invalid request"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/create-group [post]
+// @Router       /api/v1/groups [post]
 func (controller *GroupController) CreateGroup(c *gin.Context) {
 	req := requests.CreateGroupRequest{}
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+		abortWithError(c, bindingError(c, err))
 		return
 	}
 
 	data, err := controller.createGroupUsecase.CreateGroup(c, usecases.CreateGroupRequestDto{Name: req.Name, TeacherId: req.TeacherId})
 	if err != nil {
-		fmt.Println("failed to create group", err)
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
+	c.Header("Location", "/api/v1/groups/"+strconv.Itoa(data.Id))
 	c.JSON(http.StatusCreated, data)
 }
 
 // ReadAllGroups
 // @Summary      Get all groups
-// @Description  Get list of all groups (admin only)
+// @Description  Get list of all groups (requires groups.read)
 // @Tags         groups
 // @Security     BasicAuth
 // @Produce      json
+// @Param        sort query string false "Field to sort by, then by id" Enums(id, name, teacher_id)
+// @Param        order query string false "Sort order" Enums(asc, desc)
+// @Param        limit query int false "Page size (default 50, at most 500)"
+// @Param        cursor query string false "next_cursor of the previous page"
+// @Param        with_total query bool false "Also count all matching records"
+// @Param        teacher_id query int false "Groups of the teacher"
+// @Param        If-None-Match header string false "ETag of a cached copy; 304 is returned if it is still current"
 // @Success      200 {object} usecases.ReadAllGroupsResponseDto
+// @Header       200 {string} ETag "Version of the representation"
+// @Success      304 "Not modified"
+// @Failure      400 {object} object "Invalid list parameters"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/read-all-groups [get]
+// @Router       /api/v1/groups [get]
 func (controller *GroupController) ReadAllGroups(c *gin.Context) {
-	data, err := controller.readAllGroupsUsecase.ReadAllGroups(c)
+	list, ok := listRequest(c)
+	if !ok {
+		return
+	}
+
+	data, err := controller.readAllGroupsUsecase.ReadAllGroups(c, usecases.ReadAllGroupsRequestDto{ListRequest: list})
 	if err != nil {
-		fmt.Println("failed to read groups")
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, data)
+	respondWithTag(c, pageTag(data.Groups, groupKey, data.NextCursor, data.Total), data)
 }
 
 // ReadGroup
 // @Summary      Get group by ID
-// @Description  Get group by ID (student, teacher or admin). Students and teachers are allowed to access only their group.
+// @Description  Get group by ID. Requires groups.read for the group (by default its students, its teacher and admins)
 // @Tags         groups
 // @Security     BasicAuth
 // @Produce      json
-// @Param        id query int true "Group ID"
+// @Param        id path int true "Group ID"
+// @Param        include_deleted query bool false "Also return a deleted group (requires trash.manage)"
+// @Param        If-None-Match header string false "ETag of a cached copy; 304 is returned if it is still current"
 // @Success      200 {object} usecases.ReadGroupResponseDto
+// @Header       200 {string} ETag "Version of the representation"
+// @Success      304 "Not modified"
 // @Failure      400 {object} object "Invalid group ID"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Group not found"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/read-group [get]
+// @Router       /api/v1/groups/{id} [get]
 func (controller *GroupController) ReadGroup(c *gin.Context) {
-	var user any
-	var ok bool
-
-	for _, key := range []string{"student", "teacher", "admin"} {
-		if userRaw, exists := c.Get(key); exists {
-			user = userRaw
-			ok = true
-			break
-		}
-	}
-
+	id, ok := idParam(c)
 	if !ok {
-		c.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
-	idStr := c.Query("id")
-	if idStr == "" {
-		c.AbortWithStatus(http.StatusBadRequest)
+	if !authorize(c, controller.access, entities.GroupsReadPermission, usecases.Resource{GroupId: id}) {
 		return
 	}
 
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+	includeDeleted, _ := strconv.ParseBool(c.Query("include_deleted"))
+	if includeDeleted && !authorize(c, controller.access, entities.TrashManagePermission, usecases.Resource{}) {
 		return
 	}
 
-	data, err := controller.readGroupUsecase.ReadGroup(c, usecases.ReadGroupRequestDto{Id: id})
+	data, err := controller.readGroupUsecase.ReadGroup(c, usecases.ReadGroupRequestDto{Id: id, IncludeDeleted: includeDeleted})
 	if err != nil {
-		fmt.Println("failed to read group:", err)
-		c.AbortWithStatus(http.StatusInternalServerError)
-		return
-	}
-
-	switch u := user.(type) {
-	case entities.Student:
-		if u.GroupId != id {
-			c.AbortWithStatus(http.StatusForbidden)
-			return
-		}
-
-	case entities.Teacher:
-		if data.Group.TeacherId != u.Id {
-			c.AbortWithStatus(http.StatusForbidden)
-			return
-		}
-
-	case entities.Admin:
-
-	default:
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, data)
+	respondWithTag(c, entityTag(data.Group.Version), data)
 }
 
 // UpdateGroup
 // @Summary      Update group
-// @Description  Update group info (admin only)
+// @Description  Update group info (requires groups.update)
 // @Tags         groups
 // @Security     BasicAuth
 // @Accept       json
 // @Produce      json
 // @Param        group body requests.UpdateGroupRequest true "Updated group info"
+// @Param        If-Match header string false "ETag of the representation the change is based on, or *; any version if omitted"
 // @Success      200 {object} entities.Group
 // @Failure      400 {object} object "Invalid request"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Group not found"
+// @Failure      412 {object} object "Entity was changed since it was read"
 // @Failure      500 {object} object "Internal server error"
+// @Deprecated
 // @Router       /api/update-group [put]
 func (controller *GroupController) UpdateGroup(c *gin.Context) {
 	req := requests.UpdateGroupRequest{}
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	versions, ok := ifMatch(c, 1)
+	if !ok {
+		return
+	}
+
+	data, err := controller.updateGroupUsecase.UpdateGroup(c, usecases.UpdateGroupRequestDto{Id: req.Id, Version: versions[0], Name: patch.NonZero(req.Name), TeacherId: patch.NonZero(req.TeacherId)})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", entityTag(data.Group.Version))
+	c.JSON(http.StatusOK, data)
+}
+
+// PatchGroup
+// @Summary      Patch group
+// @Description  Apply a JSON merge patch (RFC 7396) to a group: absent fields are left unchanged and "teacher_id": null unassigns the teacher. name can't be cleared. Requires groups.update.
+// @Tags         groups
+// @Security     BasicAuth
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path int true "Group ID"
+// @Param        group body requests.PatchGroupRequest true "Changed fields"
+// @Param        If-Match header string true "ETag of the representation the change is based on, or *"
+// @Success      200 {object} entities.Group
+// @Failure      400 {object} object "Invalid request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Group not found"
+// @Failure      412 {object} object "Entity was changed since it was read"
+// @Failure      428 {object} object "If-Match header is missing"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/groups/{id} [patch]
+func (controller *GroupController) PatchGroup(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+
+	req := requests.PatchGroupRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
 		return
 	}
 
-	data, err := controller.updateGroupUsecase.UpdateGroup(c, usecases.UpdateGroupRequestDto{Id: req.Id, Name: req.Name, TeacherId: req.TeacherId})
+	versions, ok := ifMatch(c, 1)
+	if !ok {
+		return
+	}
+
+	data, err := controller.updateGroupUsecase.UpdateGroup(c, usecases.UpdateGroupRequestDto{Id: id, Version: versions[0], Name: req.Name, TeacherId: req.TeacherId})
 	if err != nil {
-		fmt.Println("failed to update group")
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
+	c.Header("ETag", entityTag(data.Group.Version))
 	c.JSON(http.StatusOK, data)
 }
 
 // DeleteGroup
 // @Summary      Delete group
-// @Description  Delete group by ID (admin only)
+// @Description  Delete group by ID (requires groups.delete).
+//
+//	mode defines what happens to the students of the group:
+//	unassign (default) removes them from the group, block refuses to delete
+//	a group that still has students, cascade deletes the students as well.
+//
 // @Tags         groups
 // @Security     BasicAuth
 // @Produce      json
-// @Param        id query int true "Group ID"
-// @Success      200
-// @Failure      400 {object} object "Invalid group ID"
+// @Param        id path int true "Group ID"
+// @Param        mode query string false "What to do with the students of the group" Enums(unassign, block, cascade)
+// @Param        If-Match header string true "ETag of the representation the change is based on, or *"
+// @Success      204
+// @Failure      400 {object} object "Invalid group ID or mode"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Group not found"
+// @Failure      409 {object} object "The group still has students"
+// @Failure      412 {object} object "Entity was changed since it was read"
+// @Failure      428 {object} object "If-Match header is missing"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/delete-group [delete]
+// @Router       /api/v1/groups/{id} [delete]
 func (controller *GroupController) DeleteGroup(c *gin.Context) {
-	idStr := c.Query("id")
-	if idStr == "" {
-		c.AbortWithStatus(http.StatusBadRequest)
+	id, ok := idParam(c)
+	if !ok {
 		return
 	}
 
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+	versions, ok := ifMatch(c, 1)
+	if !ok {
 		return
 	}
 
-	err = controller.deleteGroupUsecase.DeleteGroup(c, usecases.DeleteGroupRequestDto{Id: int(id)})
+	err := controller.deleteGroupUsecase.DeleteGroup(c, usecases.DeleteGroupRequestDto{Id: id, Version: versions[0], Mode: usecases.DeleteMode(c.Query("mode"))})
 	if err != nil {
-		fmt.Println("failed to delete group:", err)
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.AbortWithStatus(http.StatusOK)
+	respondDeleted(c)
 }
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"backendForKeenEye/internal/controllers/requests"
+	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/internal/usecases"
+	"backendForKeenEye/pkg/tabular"
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxImportFileSize bounds an uploaded user list; thousands of rows take a
+// few hundred kilobytes.
+const maxImportFileSize = 8 << 20
+
+var importTooLargeError = entities.NewDomainError(entities.TooLargeCode, "file is larger than 8 MB")
+
+// importColumns lists the columns of an uploaded user list; the header must
+// name the required ones.
+var (
+	importColumns         = []string{"login", "fio", "phone", "role", "group"}
+	requiredImportColumns = []string{"login", "role"}
+)
+
+// importRows turns an uploaded table into the users to create. The first row
+// is the header naming the columns in any order and case. Blank rows are
+// skipped; rows failing validation are reported together, numbered by their
+// line in the file.
+func importRows(c *gin.Context, table [][]string) ([]usecases.ImportUserRow, error) {
+	if len(table) == 0 {
+		return nil, paramError("file", "is empty")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range table[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(importColumns, name) {
+			continue
+		}
+		if _, repeated := columns[name]; repeated {
+			return nil, paramError("file", "header repeats the "+name+" column")
+		}
+		columns[name] = i
+	}
+	var missing []entities.FieldError
+	for _, name := range requiredImportColumns {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, entities.FieldError{Row: 1, Field: name, Message: "column is missing"})
+		}
+	}
+	if len(missing) > 0 {
+		return nil, usecases.ValidationError.WithFields(missing...)
+	}
+
+	var rows []usecases.ImportUserRow
+	var fieldErrors []entities.FieldError
+	for i, record := range table[1:] {
+		cell := func(name string) string {
+			column, ok := columns[name]
+			if !ok || column >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[column])
+		}
+
+		req := requests.ImportUserRow{
+			Login:       cell("login"),
+			Fio:         cell("fio"),
+			PhoneNumber: cell("phone"),
+			Role:        strings.ToLower(cell("role")),
+			Group:       cell("group"),
+		}
+		if req == (requests.ImportUserRow{}) {
+			continue
+		}
+
+		line := i + 2
+		if err := binding.Validator.ValidateStruct(req); err != nil {
+			for _, fieldErr := range entities.FieldsOf(bindingError(c, err)) {
+				fieldErr.Row = line
+				fieldErrors = append(fieldErrors, fieldErr)
+			}
+			continue
+		}
+
+		rows = append(rows, usecases.ImportUserRow{
+			Row:         line,
+			Login:       req.Login,
+			Fio:         req.Fio,
+			PhoneNumber: req.PhoneNumber,
+			Role:        req.Role,
+			GroupName:   req.Group,
+		})
+	}
+	if len(fieldErrors) > 0 {
+		return nil, usecases.ValidationError.WithFields(fieldErrors...)
+	}
+
+	return rows, nil
+}
+
+// redactedImport answers a retried import: the users created the first
+// time, without their passwords.
+type redactedImport struct {
+	usecases.ImportUsersResponseDto
+	Note string `json:"note"`
+}
+
+func redactImport(data usecases.ImportUsersResponseDto) redactedImport {
+	users := slices.Clone(data.Users)
+	for i := range users {
+		users[i].TemporaryPassword = ""
+	}
+	return redactedImport{
+		ImportUsersResponseDto: usecases.ImportUsersResponseDto{DryRun: data.DryRun, Users: users},
+		Note:                   credentialsDeliveredNote,
+	}
+}
+
+// respondWithCredentials sends the imported users as a CSV attachment, so the
+// generated passwords can be handed out. It is written like the exports, with
+// a BOM for Excel and formulas neutralized.
+func respondWithCredentials(c *gin.Context, status int, users []usecases.ImportedUser) {
+	var b bytes.Buffer
+	w, err := tabular.NewWriter(&b, tabular.CSV, tabular.Options{})
+	if err == nil {
+		err = w.Write([]string{"row", "id", "login", "role", "fio", "temporary_password"})
+	}
+	for _, user := range users {
+		if err != nil {
+			break
+		}
+		err = w.Write([]string{strconv.Itoa(user.Row), strconv.Itoa(user.Id), user.Login, user.Role, user.Fio, user.TemporaryPassword})
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="import-credentials.csv"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(status, tabular.CSV.ContentType(), b.Bytes())
+}
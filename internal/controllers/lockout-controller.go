@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"backendForKeenEye/internal/controllers/requests"
+	"backendForKeenEye/internal/usecases"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type LockoutController struct {
+	readLoginAttemptsUsecase ReadLoginAttemptsUsecase
+	unlockLoginUsecase       UnlockLoginUsecase
+}
+
+func NewLockoutController(readLoginAttemptsUsecase ReadLoginAttemptsUsecase, unlockLoginUsecase UnlockLoginUsecase) LockoutController {
+	return LockoutController{readLoginAttemptsUsecase: readLoginAttemptsUsecase, unlockLoginUsecase: unlockLoginUsecase}
+}
+
+// ReadLockouts
+// @Summary      Get lockouts
+// @Description  Logins and client addresses with recent failed authentication attempts or an active lockout (requires lockouts.read)
+// @Tags         lockouts
+// @Security     BasicAuth
+// @Produce      json
+// @Success      200 {object} usecases.ReadLoginAttemptsResponseDto
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/lockouts [get]
+func (controller *LockoutController) ReadLockouts(c *gin.Context) {
+	data, err := controller.readLoginAttemptsUsecase.ReadLoginAttempts(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// Unlock
+// @Summary      Unlock login or address
+// @Description  Clear failed attempts and lift the lockout of a login and/or a client address (requires lockouts.unlock)
+// @Tags         lockouts
+// @Security     BasicAuth
+// @Accept       json
+// @Param        target body requests.UnlockLoginRequest true "Login and/or IP address"
+// @Success      204
+// @Failure      400 {object} object "Invalid request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/lockouts/unlock [post]
+func (controller *LockoutController) Unlock(c *gin.Context) {
+	req := requests.UnlockLoginRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	err = controller.unlockLoginUsecase.UnlockLogin(c, usecases.UnlockLoginRequestDto{Login: req.Login, Ip: req.Ip})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.AbortWithStatus(http.StatusNoContent)
+}
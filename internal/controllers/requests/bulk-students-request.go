@@ -0,0 +1,11 @@
+package requests
+
+type BulkStudentsRequest struct {
+	Ids    []int `json:"ids" binding:"required,dive,gt=0"`
+	Atomic bool  `json:"atomic"`
+}
+
+type BulkAssignGroupRequest struct {
+	GroupId int `json:"group_id" binding:"required,gt=0"`
+	BulkStudentsRequest
+}
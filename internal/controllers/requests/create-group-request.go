@@ -1,6 +1,6 @@
 package requests
 
 type CreateGroupRequest struct {
-	Name      string `json:"name"`
-	TeacherId int    `json:"teacher_id"`
+	Name      string `json:"name" binding:"required,max=256"`
+	TeacherId int    `json:"teacher_id" binding:"omitempty,gt=0,teacher_exists"`
 }
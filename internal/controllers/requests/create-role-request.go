@@ -0,0 +1,6 @@
+package requests
+
+type CreateRoleRequest struct {
+	Name        string `json:"name" binding:"required,max=20"`
+	Description string `json:"description" binding:"max=256"`
+}
@@ -1,7 +1,12 @@
 package requests
 
 type CreateUserRequest struct {
-	Login    string `json:"login"`
-	Password string `json:"password"`
-	Role     string `json:"role"`
+	Login    string `json:"login" binding:"required,min=3,max=256"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role" binding:"required,max=20"`
+	Email    string `json:"email" binding:"omitempty,email,max=256"`
+
+	Fio         string `json:"fio" binding:"omitempty,max=256,fio"`
+	PhoneNumber string `json:"phone_number" binding:"omitempty,e164"`
+	GroupId     int    `json:"group_id" binding:"omitempty,gt=0,group_exists"`
 }
@@ -0,0 +1,15 @@
+package requests
+
+// ExportRequest holds the query parameters of the export endpoints: the list
+// filters and order, the columns as a comma separated list and the format,
+// which overrides the Accept header.
+type ExportRequest struct {
+	Format    string `form:"format" binding:"omitempty,oneof=csv xlsx pdf"`
+	Columns   string `form:"columns" binding:"omitempty,max=256"`
+	Sort      string `form:"sort" binding:"omitempty,max=32"`
+	Order     string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Fio       string `form:"fio" binding:"omitempty,max=256"`
+	GroupId   int    `form:"group_id" binding:"omitempty,gt=0"`
+	TeacherId int    `form:"teacher_id" binding:"omitempty,gt=0"`
+	NoGroup   bool   `form:"no_group"`
+}
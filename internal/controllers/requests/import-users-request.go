@@ -0,0 +1,15 @@
+package requests
+
+type ImportUsersRequest struct {
+	DryRun bool   `form:"dry_run"`
+	Result string `form:"result" binding:"omitempty,oneof=json csv"`
+}
+
+// ImportUserRow is a row of an uploaded user list, keyed by its column names.
+type ImportUserRow struct {
+	Login       string `json:"login" binding:"required,min=3,max=256"`
+	Fio         string `json:"fio" binding:"omitempty,max=256,fio"`
+	PhoneNumber string `json:"phone" binding:"omitempty,e164"`
+	Role        string `json:"role" binding:"required,oneof=student teacher"`
+	Group       string `json:"group" binding:"omitempty,max=256"`
+}
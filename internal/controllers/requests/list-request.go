@@ -0,0 +1,14 @@
+package requests
+
+// ListRequest holds the query parameters shared by the list endpoints.
+type ListRequest struct {
+	Sort      string `form:"sort" binding:"omitempty,max=32"`
+	Order     string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=500"`
+	Cursor    string `form:"cursor" binding:"omitempty,max=512"`
+	WithTotal bool   `form:"with_total"`
+	Fio       string `form:"fio" binding:"omitempty,max=256"`
+	GroupId   int    `form:"group_id" binding:"omitempty,gt=0"`
+	TeacherId int    `form:"teacher_id" binding:"omitempty,gt=0"`
+	NoGroup   bool   `form:"no_group"`
+}
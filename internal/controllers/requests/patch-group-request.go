@@ -0,0 +1,8 @@
+package requests
+
+import "backendForKeenEye/pkg/patch"
+
+type PatchGroupRequest struct {
+	Name      patch.Field[string] `json:"name" binding:"omitempty,max=256" swaggertype:"string"`
+	TeacherId patch.Field[int]    `json:"teacher_id" binding:"omitempty,gt=0,teacher_exists" swaggertype:"integer"`
+}
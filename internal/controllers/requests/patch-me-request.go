@@ -0,0 +1,9 @@
+package requests
+
+import "backendForKeenEye/pkg/patch"
+
+type PatchMeRequest struct {
+	Fio         patch.Field[string] `json:"fio" binding:"omitempty,max=256,fio" swaggertype:"string"`
+	PhoneNumber patch.Field[string] `json:"phone_number" binding:"omitempty,e164" swaggertype:"string"`
+	Email       patch.Field[string] `json:"email" binding:"omitempty,email,max=256" swaggertype:"string"`
+}
@@ -0,0 +1,9 @@
+package requests
+
+import "backendForKeenEye/pkg/patch"
+
+type PatchStudentRequest struct {
+	Fio         patch.Field[string] `json:"fio" binding:"omitempty,max=256,fio" swaggertype:"string"`
+	PhoneNumber patch.Field[string] `json:"phone_number" binding:"omitempty,e164" swaggertype:"string"`
+	GroupId     patch.Field[int]    `json:"group_id" binding:"omitempty,gt=0,group_exists" swaggertype:"integer"`
+}
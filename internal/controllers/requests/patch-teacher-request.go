@@ -0,0 +1,8 @@
+package requests
+
+import "backendForKeenEye/pkg/patch"
+
+type PatchTeacherRequest struct {
+	Fio         patch.Field[string] `json:"fio" binding:"omitempty,max=256,fio" swaggertype:"string"`
+	PhoneNumber patch.Field[string] `json:"phone_number" binding:"omitempty,e164" swaggertype:"string"`
+}
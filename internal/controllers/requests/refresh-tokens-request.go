@@ -0,0 +1,5 @@
+package requests
+
+type RefreshTokensRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
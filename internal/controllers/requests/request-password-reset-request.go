@@ -0,0 +1,5 @@
+package requests
+
+type RequestPasswordResetRequest struct {
+	Login string `json:"login" binding:"required"`
+}
@@ -0,0 +1,6 @@
+package requests
+
+type SearchRequest struct {
+	Query string `form:"q" binding:"required,max=256"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
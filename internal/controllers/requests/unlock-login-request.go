@@ -0,0 +1,6 @@
+package requests
+
+type UnlockLoginRequest struct {
+	Login string `json:"login" binding:"max=256"`
+	Ip    string `json:"ip" binding:"omitempty,ip"`
+}
@@ -1,7 +1,7 @@
 package requests
 
 type UpdateAdminRequest struct {
-	Id          int    `json:"id"`
-	Fio         string `json:"fio"`
-	PhoneNumber string `json:"phone_number"`
+	Id          int    `json:"id" binding:"required,gt=0"`
+	Fio         string `json:"fio" binding:"omitempty,max=256,fio"`
+	PhoneNumber string `json:"phone_number" binding:"omitempty,e164"`
 }
@@ -1,7 +1,7 @@
 package requests
 
 type UpdateGroupRequest struct {
-	Id        int    `json:"id"`
-	Name      string `json:"name"`
-	TeacherId int    `json:"teacher_id"`
+	Id        int    `json:"id" binding:"required,gt=0"`
+	Name      string `json:"name" binding:"max=256"`
+	TeacherId int    `json:"teacher_id" binding:"omitempty,gt=0,teacher_exists"`
 }
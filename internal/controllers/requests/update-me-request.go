@@ -0,0 +1,7 @@
+package requests
+
+type UpdateMeRequest struct {
+	Fio         string `json:"fio" binding:"omitempty,max=256,fio"`
+	PhoneNumber string `json:"phone_number" binding:"omitempty,e164"`
+	Email       string `json:"email" binding:"omitempty,email,max=256"`
+}
@@ -0,0 +1,10 @@
+package requests
+
+type UpdateRoleGrantsRequest struct {
+	Grants []GrantRequest `json:"grants" binding:"required,dive"`
+}
+
+type GrantRequest struct {
+	Permission string `json:"permission" binding:"required"`
+	Scope      string `json:"scope" binding:"required,oneof=all group self"`
+}
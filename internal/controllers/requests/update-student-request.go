@@ -1,8 +1,8 @@
 package requests
 
 type UpdateStudentRequest struct {
-	Id          int    `json:"id"`
-	Fio         string `json:"fio"`
-	PhoneNumber string `json:"phone_number"`
-	GroupId     int    `json:"group_id"`
+	Id          int    `json:"id" binding:"required,gt=0"`
+	Fio         string `json:"fio" binding:"omitempty,max=256,fio"`
+	PhoneNumber string `json:"phone_number" binding:"omitempty,e164"`
+	GroupId     int    `json:"group_id" binding:"omitempty,gt=0,group_exists"`
 }
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"backendForKeenEye/internal/controllers/requests"
+	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/internal/usecases"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type RoleController struct {
+	readRolesUsecase        ReadRolesUsecase
+	createRoleUsecase       CreateRoleUsecase
+	updateRoleGrantsUsecase UpdateRoleGrantsUsecase
+}
+
+func NewRoleController(readRolesUsecase ReadRolesUsecase, createRoleUsecase CreateRoleUsecase, updateRoleGrantsUsecase UpdateRoleGrantsUsecase) RoleController {
+	return RoleController{readRolesUsecase: readRolesUsecase, createRoleUsecase: createRoleUsecase, updateRoleGrantsUsecase: updateRoleGrantsUsecase}
+}
+
+// ReadRoles
+// @Summary      Get roles
+// @Description  Roles with their granted permissions and the list of all known permissions (requires roles.manage)
+// @Tags         roles
+// @Security     BasicAuth
+// @Produce      json
+// @Success      200 {object} usecases.ReadRolesResponseDto
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/roles [get]
+func (controller *RoleController) ReadRoles(c *gin.Context) {
+	data, err := controller.readRolesUsecase.ReadRoles(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// CreateRole
+// @Summary      Create role
+// @Description  Create a role without permissions (requires roles.manage). Names are lowercase latin letters, digits and underscores.
+// @Tags         roles
+// @Security     BasicAuth
+// @Accept       json
+// @Produce      json
+// @Param        role body requests.CreateRoleRequest true "Role info"
+// @Success      201 {object} entities.Role
+// @Failure      400 {object} object "Invalid request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      409 {object} object "Role already exists"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/roles [post]
+func (controller *RoleController) CreateRole(c *gin.Context) {
+	req := requests.CreateRoleRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	data, err := controller.createRoleUsecase.CreateRole(c, usecases.CreateRoleRequestDto{Name: req.Name, Description: req.Description})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, data)
+}
+
+// UpdateRoleGrants
+// @Summary      Set role permissions
+// @Description  Replace the permissions of a role (requires roles.manage). Scope is one of all, group or self. The caller's own role must keep roles.manage with scope all.
+// @Tags         roles
+// @Security     BasicAuth
+// @Accept       json
+// @Param        name path string true "Role name"
+// @Param        grants body requests.UpdateRoleGrantsRequest true "Permissions of the role"
+// @Success      204
+// @Failure      400 {object} object "Invalid request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Role not found"
+// @Failure      409 {object} object "Would revoke role management from own role"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/roles/{name}/permissions [put]
+func (controller *RoleController) UpdateRoleGrants(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	req := requests.UpdateRoleGrantsRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	grants := make([]entities.Grant, 0, len(req.Grants))
+	for _, grant := range req.Grants {
+		grants = append(grants, entities.Grant{Permission: grant.Permission, Scope: entities.Scope(grant.Scope)})
+	}
+
+	err = controller.updateRoleGrantsUsecase.UpdateRoleGrants(c, usecases.UpdateRoleGrantsRequestDto{User: user, Role: c.Param("name"), Grants: grants})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.AbortWithStatus(http.StatusNoContent)
+}
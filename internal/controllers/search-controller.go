@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"backendForKeenEye/internal/controllers/requests"
+	"backendForKeenEye/internal/usecases"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type SearchController struct {
+	searchUsecase SearchUsecase
+}
+
+func NewSearchController(searchUsecase SearchUsecase) SearchController {
+	return SearchController{searchUsecase: searchUsecase}
+}
+
+// Search
+// @Summary      Search
+// @Description  Find students (by fio, login or phone number), teachers (by fio, login or phone number) and groups (by name), best matches first.
+// @Description  Matching is case-insensitive, treats ё as е and tolerates typos. Only records the caller may read are returned: e.g. teachers find the students of their groups.
+// @Tags         search
+// @Security     BasicAuth
+// @Produce      json
+// @Param        q query string true "Text to look for, at least 2 characters"
+// @Param        limit query int false "Maximum number of hits (default 20, at most 100)"
+// @Success      200 {object} usecases.SearchResponseDto
+// @Failure      400 {object} object "Invalid query"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/search [get]
+func (controller *SearchController) Search(c *gin.Context) {
+	req := requests.SearchRequest{}
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	data, err := controller.searchUsecase.Search(c, usecases.SearchRequestDto{User: user, Query: req.Query, Limit: req.Limit})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
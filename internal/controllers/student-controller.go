@@ -4,297 +4,412 @@ import (
 	"backendForKeenEye/internal/controllers/requests"
 	"backendForKeenEye/internal/entities"
 	"backendForKeenEye/internal/usecases"
-	"fmt"
+	"backendForKeenEye/pkg/patch"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
 )
 
 type StudentController struct {
-	readGroupUsecase                ReadGroupUsecase
+	access                          AccessController
 	readAllStudentsUsecase          ReadAllStudentsUsecase
 	readAllStudentsByGroupIdUsecase ReadAllStudentsByGroupIdUsecase
 	readStudentUsecase              ReadStudentUsecase
 	updateStudentUsecase            UpdateStudentUsecase
 	deleteStudentUsecase            DeleteStudentUsecase
+	bulkAssignStudentsUsecase       BulkAssignStudentsUsecase
+	bulkDeleteStudentsUsecase       BulkDeleteStudentsUsecase
+	bulkRestoreStudentsUsecase      BulkRestoreStudentsUsecase
 }
 
-func NewStudentController(readGroupUsecase ReadGroupUsecase, readAllStudentsUsecase ReadAllStudentsUsecase, readAllStudentsByGroupIdUsecase ReadAllStudentsByGroupIdUsecase, readStudentUsecase ReadStudentUsecase, updateStudentUsecase UpdateStudentUsecase, deleteStudentUsecase DeleteStudentUsecase) StudentController {
-	return StudentController{readGroupUsecase: readGroupUsecase, readAllStudentsUsecase: readAllStudentsUsecase, readAllStudentsByGroupIdUsecase: readAllStudentsByGroupIdUsecase, readStudentUsecase: readStudentUsecase, updateStudentUsecase: updateStudentUsecase, deleteStudentUsecase: deleteStudentUsecase}
+func NewStudentController(access AccessController, readAllStudentsUsecase ReadAllStudentsUsecase, readAllStudentsByGroupIdUsecase ReadAllStudentsByGroupIdUsecase, readStudentUsecase ReadStudentUsecase, updateStudentUsecase UpdateStudentUsecase, deleteStudentUsecase DeleteStudentUsecase, bulkAssignStudentsUsecase BulkAssignStudentsUsecase, bulkDeleteStudentsUsecase BulkDeleteStudentsUsecase, bulkRestoreStudentsUsecase BulkRestoreStudentsUsecase) StudentController {
+	return StudentController{access: access, readAllStudentsUsecase: readAllStudentsUsecase, readAllStudentsByGroupIdUsecase: readAllStudentsByGroupIdUsecase, readStudentUsecase: readStudentUsecase, updateStudentUsecase: updateStudentUsecase, deleteStudentUsecase: deleteStudentUsecase, bulkAssignStudentsUsecase: bulkAssignStudentsUsecase, bulkDeleteStudentsUsecase: bulkDeleteStudentsUsecase, bulkRestoreStudentsUsecase: bulkRestoreStudentsUsecase}
 }
 
 // ReadAllStudents
 // @Summary      Get all students
-// @Description  Returns list of all students (admin only)
+// @Description  Returns list of all students (requires students.read)
 // @Tags         students
 // @Security     BasicAuth
 // @Produce      json
+// @Param        sort query string false "Field to sort by, then by id" Enums(id, fio, group_id)
+// @Param        order query string false "Sort order" Enums(asc, desc)
+// @Param        limit query int false "Page size (default 50, at most 500)"
+// @Param        cursor query string false "next_cursor of the previous page"
+// @Param        with_total query bool false "Also count all matching records"
+// @Param        fio query string false "Part of the fio, case-insensitive"
+// @Param        group_id query int false "Students of the group"
+// @Param        teacher_id query int false "Students of the groups of the teacher"
+// @Param        no_group query bool false "Only students without a group"
+// @Param        If-None-Match header string false "ETag of a cached copy; 304 is returned if it is still current"
 // @Success      200 {array} entities.Student
+// @Header       200 {string} ETag "Version of the representation"
+// @Success      304 "Not modified"
+// @Failure      400 {object} object "Invalid list parameters"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/read-all-students [get]
+// @Router       /api/v1/students [get]
 func (controller *StudentController) ReadAllStudents(c *gin.Context) {
-	data, err := controller.readAllStudentsUsecase.ReadAllStudents(c)
+	list, ok := listRequest(c)
+	if !ok {
+		return
+	}
+
+	data, err := controller.readAllStudentsUsecase.ReadAllStudents(c, usecases.ReadAllStudentsRequestDto{ListRequest: list})
 	if err != nil {
-		fmt.Println("failed to read students")
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, data)
+	respondWithTag(c, pageTag(data.Students, studentKey, data.NextCursor, data.Total), data)
 }
 
 // ReadAllStudentsByGroupId
 // @Summary      Get students by group ID
-// @Description  Students of group (student sees own group, teacher sees own group, admin sees all)
+// @Description  Students of group. Requires groups.students.read for the group (by default members of the group and admins)
 // @Tags         students
 // @Security     BasicAuth
 // @Produce      json
-// @Param        id query int true "Group ID"
+// @Param        id path int true "Group ID"
+// @Param        sort query string false "Field to sort by, then by id" Enums(id, fio, group_id)
+// @Param        order query string false "Sort order" Enums(asc, desc)
+// @Param        limit query int false "Page size (default 50, at most 500)"
+// @Param        cursor query string false "next_cursor of the previous page"
+// @Param        with_total query bool false "Also count all matching records"
+// @Param        fio query string false "Part of the fio, case-insensitive"
+// @Param        If-None-Match header string false "ETag of a cached copy; 304 is returned if it is still current"
 // @Success      200 {object} usecases.ReadAllStudentsByGroupIdResponseDto
-// @Failure      400 {object} object "Invalid group ID"
+// @Header       200 {string} ETag "Version of the representation"
+// @Success      304 "Not modified"
+// @Failure      400 {object} object "Invalid group ID or list parameters"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/read-all-students-by-group-id [get]
+// @Router       /api/v1/groups/{id}/students [get]
 func (controller *StudentController) ReadAllStudentsByGroupId(c *gin.Context) {
-	var user any
-	var ok bool
-
-	for _, key := range []string{"student", "teacher", "admin"} {
-		if userRaw, exists := c.Get(key); exists {
-			user = userRaw
-			ok = true
-			break
-		}
-	}
-
+	groupId, ok := idParam(c)
 	if !ok {
-		c.AbortWithStatus(http.StatusUnauthorized)
-		return
-	}
-
-	groupIdStr := c.Query("id")
-	if groupIdStr == "" {
-		c.AbortWithStatus(http.StatusBadRequest)
 		return
 	}
 
-	groupId, err := strconv.Atoi(groupIdStr)
-	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+	if !authorize(c, controller.access, entities.GroupStudentsReadPermission, usecases.Resource{GroupId: groupId}) {
 		return
 	}
 
-	switch u := user.(type) {
-	case entities.Student:
-		if u.GroupId != groupId {
-			c.AbortWithStatus(http.StatusForbidden)
-			return
-		}
-
-	case entities.Teacher:
-		group, err := controller.readGroupUsecase.ReadGroup(c, usecases.ReadGroupRequestDto{Id: groupId})
-		if err != nil || group.Group.TeacherId != u.Id {
-			c.AbortWithStatus(http.StatusForbidden)
-			return
-		}
-
-	case entities.Admin:
-
-	default:
-		c.AbortWithStatus(http.StatusInternalServerError)
+	list, ok := listRequest(c)
+	if !ok {
 		return
 	}
 
 	data, err := controller.readAllStudentsByGroupIdUsecase.ReadAllStudentsByGroupId(
-		c, usecases.ReadAllStudentsByGroupIdRequestDto{GroupId: groupId},
+		c, usecases.ReadAllStudentsByGroupIdRequestDto{GroupId: groupId, ListRequest: list},
 	)
 	if err != nil {
-		fmt.Println("failed to read students:", err)
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, data)
+	respondWithTag(c, pageTag(data.Students, studentKey, data.NextCursor, data.Total), data)
 }
 
 // ReadStudent
 // @Summary      Get student by ID
-// @Description  Returns student by ID. Accessible by student (self), teacher of group, or admin
+// @Description  Returns student by ID. Requires students.read for the student (by default the student, teacher of the group and admins)
 // @Tags         students
 // @Security     BasicAuth
 // @Produce      json
-// @Param        id query int true "Student ID"
+// @Param        id path int true "Student ID"
+// @Param        include_deleted query bool false "Also return a deleted student (requires trash.manage)"
+// @Param        If-None-Match header string false "ETag of a cached copy; 304 is returned if it is still current"
 // @Success      200 {object} usecases.ReadStudentResponseDto
+// @Header       200 {string} ETag "Version of the representation"
+// @Success      304 "Not modified"
 // @Failure      400 {object} object "Invalid student ID"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Student not found"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/read-student [get]
+// @Router       /api/v1/students/{id} [get]
 func (controller *StudentController) ReadStudent(c *gin.Context) {
-	var user any
-	var ok bool
-
-	for _, key := range []string{"student", "teacher", "admin"} {
-		if userRaw, exists := c.Get(key); exists {
-			user = userRaw
-			ok = true
-			break
-		}
-	}
-
+	id, ok := idParam(c)
 	if !ok {
-		c.AbortWithStatus(http.StatusUnauthorized)
-		return
-	}
-
-	idStr := c.Query("id")
-	if idStr == "" {
-		c.AbortWithStatus(http.StatusBadRequest)
 		return
 	}
 
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+	includeDeleted, _ := strconv.ParseBool(c.Query("include_deleted"))
+	if includeDeleted && !authorize(c, controller.access, entities.TrashManagePermission, usecases.Resource{}) {
 		return
 	}
 
-	data, err := controller.readStudentUsecase.ReadStudent(c, usecases.ReadStudentRequestDto{Id: int(id)})
+	data, err := controller.readStudentUsecase.ReadStudent(c, usecases.ReadStudentRequestDto{Id: id, IncludeDeleted: includeDeleted})
 	if err != nil {
-		fmt.Println("failed to read student:", err)
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	switch u := user.(type) {
-	case entities.Student:
-		if u.Id != int(id) || u.GroupId != data.Student.GroupId {
-			c.AbortWithStatus(http.StatusForbidden)
-			return
-		}
-
-	case entities.Teacher:
-		group, err := controller.readGroupUsecase.ReadGroup(c, usecases.ReadGroupRequestDto{Id: data.Student.GroupId})
-		if err != nil || group.Group.TeacherId != u.Id {
-			c.AbortWithStatus(http.StatusForbidden)
-			return
-		}
-
-	case entities.Admin:
-
-	default:
-		c.AbortWithStatus(http.StatusInternalServerError)
+	if !authorize(c, controller.access, entities.StudentsReadPermission, usecases.Resource{OwnerId: id, GroupId: data.Student.GroupId}) {
 		return
 	}
 
-	c.JSON(http.StatusOK, data)
+	respondWithTag(c, entityTag(data.Student.Version), data)
 }
 
 // UpdateStudent
 // @Summary      Update student
 // @Description  Update a student record.
 //
-//	Access allowed to:
-//	- The student themselves
-//	- Admins
-//	- Teachers
+//	Requires the students.update permission for the student:
+//	by default the student themselves, teachers of their group and admins.
+//	Students editing their own record may change contact fields only.
+//	Changing group_id requires students.transfer on the current and the new group.
 //
 // @Tags         students
 // @Security     BasicAuth
 // @Accept       json
 // @Produce      json
 // @Param        student body requests.UpdateStudentRequest true "Updated student info"
+// @Param        If-Match header string false "ETag of the representation the change is based on, or *; any version if omitted"
 // @Success      200 {object} entities.Student
 // @Failure      400 {object} object "Invalid request body"
 // @Failure      403 {object} object "Access forbidden"
 // @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Student not found"
+// @Failure      412 {object} object "Entity was changed since it was read"
 // @Failure      500 {object} object "Internal server error"
+// @Deprecated
 // @Router       /api/update-student [put]
 func (controller *StudentController) UpdateStudent(c *gin.Context) {
-	var user any
-	var ok bool
-
-	for _, key := range []string{"student", "teacher", "admin"} {
-		if userRaw, exists := c.Get(key); exists {
-			user = userRaw
-			ok = true
-			break
-		}
-	}
-
+	user, ok := currentUser(c)
 	if !ok {
-		c.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
 	req := requests.UpdateStudentRequest{}
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	versions, ok := ifMatch(c, 1)
+	if !ok {
+		return
+	}
+
+	data, err := controller.updateStudentUsecase.UpdateStudent(c, usecases.UpdateStudentRequestDto{User: user, Id: req.Id, Version: versions[0], Fio: patch.NonZero(req.Fio), PhoneNumber: patch.NonZero(req.PhoneNumber), GroupId: patch.NonZero(req.GroupId)})
+	if err != nil {
+		abortWithError(c, err)
 		return
 	}
 
-	switch u := user.(type) {
-	case entities.Student:
-		if u.Id != req.Id {
-			c.AbortWithStatus(http.StatusForbidden)
-			return
-		}
+	c.Header("ETag", entityTag(data.Student.Version))
+	c.JSON(http.StatusOK, data)
+}
+
+// PatchStudent
+// @Summary      Patch student
+// @Description  Apply a JSON merge patch (RFC 7396) to a student record.
+//
+//	Absent fields are left unchanged and null clears a field: "group_id": null
+//	removes the student from their group. fio can't be cleared.
+//	The permission rules are the same as for update-student; removing a student
+//	from a group needs students.transfer on that group.
+//
+// @Tags         students
+// @Security     BasicAuth
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path int true "Student ID"
+// @Param        student body requests.PatchStudentRequest true "Changed fields"
+// @Param        If-Match header string true "ETag of the representation the change is based on, or *"
+// @Success      200 {object} entities.Student
+// @Failure      400 {object} object "Invalid request body"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Access forbidden"
+// @Failure      404 {object} object "Student not found"
+// @Failure      412 {object} object "Entity was changed since it was read"
+// @Failure      428 {object} object "If-Match header is missing"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/students/{id} [patch]
+func (controller *StudentController) PatchStudent(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
 
-	case entities.Teacher:
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
 
-	case entities.Admin:
+	req := requests.PatchStudentRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
 
-	default:
-		c.AbortWithStatus(http.StatusInternalServerError)
+	versions, ok := ifMatch(c, 1)
+	if !ok {
 		return
 	}
 
-	data, err := controller.updateStudentUsecase.UpdateStudent(c, usecases.UpdateStudentRequestDto{Id: req.Id, Fio: req.Fio, PhoneNumber: req.PhoneNumber, GroupId: req.GroupId})
+	data, err := controller.updateStudentUsecase.UpdateStudent(c, usecases.UpdateStudentRequestDto{User: user, Id: id, Version: versions[0], Fio: req.Fio, PhoneNumber: req.PhoneNumber, GroupId: req.GroupId})
 	if err != nil {
-		fmt.Println("failed to update student")
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
+	c.Header("ETag", entityTag(data.Student.Version))
 	c.JSON(http.StatusOK, data)
 }
 
 // DeleteStudent
 // @Summary      Delete student
-// @Description  Delete student by ID (admin only)
+// @Description  Delete student by ID (requires students.delete)
 // @Tags         students
 // @Security     BasicAuth
 // @Produce      json
-// @Param        id query int true "Student ID"
-// @Success      200
+// @Param        id path int true "Student ID"
+// @Param        If-Match header string true "ETag of the representation the change is based on, or *"
+// @Success      204
 // @Failure      400 {object} object "Invalid student ID"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Student not found"
+// @Failure      412 {object} object "Entity was changed since it was read"
+// @Failure      428 {object} object "If-Match header is missing"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/delete-student [delete]
+// @Router       /api/v1/students/{id} [delete]
 func (controller *StudentController) DeleteStudent(c *gin.Context) {
-	idStr := c.Query("id")
-	if idStr == "" {
-		c.AbortWithStatus(http.StatusBadRequest)
+	id, ok := idParam(c)
+	if !ok {
 		return
 	}
 
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	versions, ok := ifMatch(c, 1)
+	if !ok {
+		return
+	}
+
+	err := controller.deleteStudentUsecase.DeleteStudent(c, usecases.DeleteStudentRequestDto{Id: id, Version: versions[0]})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	respondDeleted(c)
+}
+
+// BulkAssignGroup
+// @Summary      Move students to a group
+// @Description  Move many students to a group in one transaction.
+//
+//	Requires students.transfer on the target group and on the current group of each student.
+//	Each student is reported as done or failed with the error code; with atomic set
+//	a single failure rolls all of them back. At most bulk.max_items ids per request.
+//
+// @Tags         students
+// @Security     BasicAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body requests.BulkAssignGroupRequest true "Students and the group"
+// @Success      200 {object} usecases.BulkResponseDto
+// @Failure      400 {object} object "Invalid request body"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Group not found"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/students/bulk/assign-group [post]
+func (controller *StudentController) BulkAssignGroup(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	req := requests.BulkAssignGroupRequest{}
+	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+		abortWithError(c, bindingError(c, err))
 		return
 	}
 
-	err = controller.deleteStudentUsecase.DeleteStudent(c, usecases.DeleteStudentRequestDto{Id: int(id)})
+	data, err := controller.bulkAssignStudentsUsecase.BulkAssignStudents(c, usecases.BulkAssignStudentsRequestDto{
+		User:        user,
+		GroupId:     req.GroupId,
+		BulkRequest: usecases.BulkRequest{Ids: req.Ids, Atomic: req.Atomic},
+	})
 	if err != nil {
-		fmt.Println("failed to delete student:", err)
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.AbortWithStatus(http.StatusOK)
+	c.JSON(http.StatusOK, data)
+}
+
+// BulkDelete
+// @Summary      Delete students
+// @Description  Move many students to the trash in one transaction (requires students.delete). Each student is reported as done or failed with the error code; with atomic set a single failure rolls all of them back. At most bulk.max_items ids per request.
+// @Tags         students
+// @Security     BasicAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body requests.BulkStudentsRequest true "Students"
+// @Success      200 {object} usecases.BulkResponseDto
+// @Failure      400 {object} object "Invalid request body"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/students/bulk/delete [post]
+func (controller *StudentController) BulkDelete(c *gin.Context) {
+	req := requests.BulkStudentsRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	data, err := controller.bulkDeleteStudentsUsecase.BulkDeleteStudents(c, usecases.BulkDeleteStudentsRequestDto{
+		BulkRequest: usecases.BulkRequest{Ids: req.Ids, Atomic: req.Atomic},
+	})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// BulkRestore
+// @Summary      Restore students
+// @Description  Take many students out of the trash in one transaction (requires trash.manage). Each student is reported as done or failed with the error code; with atomic set a single failure rolls all of them back. At most bulk.max_items ids per request.
+// @Tags         students
+// @Security     BasicAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body requests.BulkStudentsRequest true "Students"
+// @Success      200 {object} usecases.BulkResponseDto
+// @Failure      400 {object} object "Invalid request body"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/students/bulk/restore [post]
+func (controller *StudentController) BulkRestore(c *gin.Context) {
+	req := requests.BulkStudentsRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	data, err := controller.bulkRestoreStudentsUsecase.BulkRestoreStudents(c, usecases.BulkRestoreStudentsRequestDto{
+		BulkRequest: usecases.BulkRequest{Ids: req.Ids, Atomic: req.Atomic},
+	})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
 }
@@ -4,208 +4,239 @@ import (
 	"backendForKeenEye/internal/controllers/requests"
 	"backendForKeenEye/internal/entities"
 	"backendForKeenEye/internal/usecases"
-	"fmt"
+	"backendForKeenEye/pkg/patch"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
 )
 
 type TeacherController struct {
+	access                 AccessController
 	readAllTeachersUsecase ReadAllTeachersUsecase
 	readTeacherUsecase     ReadTeacherUsecase
 	updateTeacherUsecase   UpdateTeacherUsecase
 	deleteTeacherUsecase   DeleteTeacherUsecase
 }
 
-func NewTeacherController(readAllTeachersUsecase ReadAllTeachersUsecase, readTeacherUsecase ReadTeacherUsecase, updateTeacherUsecase UpdateTeacherUsecase, deleteTeacherUsecase DeleteTeacherUsecase) TeacherController {
-	return TeacherController{readAllTeachersUsecase: readAllTeachersUsecase, readTeacherUsecase: readTeacherUsecase, updateTeacherUsecase: updateTeacherUsecase, deleteTeacherUsecase: deleteTeacherUsecase}
+func NewTeacherController(access AccessController, readAllTeachersUsecase ReadAllTeachersUsecase, readTeacherUsecase ReadTeacherUsecase, updateTeacherUsecase UpdateTeacherUsecase, deleteTeacherUsecase DeleteTeacherUsecase) TeacherController {
+	return TeacherController{access: access, readAllTeachersUsecase: readAllTeachersUsecase, readTeacherUsecase: readTeacherUsecase, updateTeacherUsecase: updateTeacherUsecase, deleteTeacherUsecase: deleteTeacherUsecase}
 }
 
 // ReadAllTeachers
 // @Summary      Get all teachers
-// @Description  Returns list of all teachers (admin only)
+// @Description  Returns list of all teachers (requires teachers.read)
 // @Tags         teachers
 // @Security     BasicAuth
 // @Produce      json
+// @Param        sort query string false "Field to sort by, then by id" Enums(id, fio)
+// @Param        order query string false "Sort order" Enums(asc, desc)
+// @Param        limit query int false "Page size (default 50, at most 500)"
+// @Param        cursor query string false "next_cursor of the previous page"
+// @Param        with_total query bool false "Also count all matching records"
+// @Param        fio query string false "Part of the fio, case-insensitive"
+// @Param        no_group query bool false "Only teachers that teach no group"
+// @Param        If-None-Match header string false "ETag of a cached copy; 304 is returned if it is still current"
 // @Success      200 {array} entities.Teacher
+// @Header       200 {string} ETag "Version of the representation"
+// @Success      304 "Not modified"
+// @Failure      400 {object} object "Invalid list parameters"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/read-all-teachers [get]
+// @Router       /api/v1/teachers [get]
 func (controller *TeacherController) ReadAllTeachers(c *gin.Context) {
-	data, err := controller.readAllTeachersUsecase.ReadAllTeachers(c)
+	list, ok := listRequest(c)
+	if !ok {
+		return
+	}
+
+	data, err := controller.readAllTeachersUsecase.ReadAllTeachers(c, usecases.ReadAllTeachersRequestDto{ListRequest: list})
 	if err != nil {
-		fmt.Println("failed to read teachers")
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, data)
+	respondWithTag(c, pageTag(data.Teachers, teacherKey, data.NextCursor, data.Total), data)
 }
 
 // ReadTeacher
 // @Summary      Get teacher by ID
-// @Description  Get teacher by ID (teacher sees self, admin sees all, students forbidden)
+// @Description  Get teacher by ID. Requires teachers.read for the teacher (by default the teacher themselves and admins)
 // @Tags         teachers
 // @Security     BasicAuth
 // @Produce      json
-// @Param        id query int true "Teacher ID"
+// @Param        id path int true "Teacher ID"
+// @Param        include_deleted query bool false "Also return a deleted teacher (requires trash.manage)"
+// @Param        If-None-Match header string false "ETag of a cached copy; 304 is returned if it is still current"
 // @Success      200 {object} usecases.ReadTeacherResponseDto
+// @Header       200 {string} ETag "Version of the representation"
+// @Success      304 "Not modified"
 // @Failure      400 {object} object "Invalid teacher ID"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Teacher not found"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/read-teacher [get]
+// @Router       /api/v1/teachers/{id} [get]
 func (controller *TeacherController) ReadTeacher(c *gin.Context) {
-	var user any
-	var ok bool
-
-	for _, key := range []string{"teacher", "admin"} {
-		if userRaw, exists := c.Get(key); exists {
-			user = userRaw
-			ok = true
-			break
-		}
-	}
-
+	id, ok := idParam(c)
 	if !ok {
-		c.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
-	idStr := c.Query("id")
-	if idStr == "" {
-		c.AbortWithStatus(http.StatusBadRequest)
+	if !authorize(c, controller.access, entities.TeachersReadPermission, usecases.Resource{OwnerId: id}) {
 		return
 	}
 
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+	includeDeleted, _ := strconv.ParseBool(c.Query("include_deleted"))
+	if includeDeleted && !authorize(c, controller.access, entities.TrashManagePermission, usecases.Resource{}) {
 		return
 	}
 
-	switch u := user.(type) {
-
-	case entities.Teacher:
-		if u.Id != int(id) {
-			c.AbortWithStatus(http.StatusForbidden)
-			return
-		}
-
-	case entities.Admin:
-
-	default:
-		c.AbortWithStatus(http.StatusInternalServerError)
-		return
-	}
-
-	data, err := controller.readTeacherUsecase.ReadTeacher(c, usecases.ReadTeacherRequestDto{Id: int(id)})
+	data, err := controller.readTeacherUsecase.ReadTeacher(c, usecases.ReadTeacherRequestDto{Id: id, IncludeDeleted: includeDeleted})
 	if err != nil {
-		fmt.Println("failed to read teacher:", err)
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, data)
+	respondWithTag(c, entityTag(data.Teacher.Version), data)
 }
 
 // UpdateTeacher
 // @Summary      Update teacher
-// @Description  Update teacher info (teacher updates self, admin updates any)
+// @Description  Update teacher info. Requires teachers.update for the teacher (by default the teacher themselves and admins)
 // @Tags         teachers
 // @Security     BasicAuth
 // @Accept       json
 // @Produce      json
 // @Param        teacher body requests.UpdateTeacherRequest true "Updated teacher info"
+// @Param        If-Match header string false "ETag of the representation the change is based on, or *; any version if omitted"
 // @Success      200 {object} entities.Teacher
 // @Failure      400 {object} object "Invalid request"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Teacher not found"
+// @Failure      412 {object} object "Entity was changed since it was read"
 // @Failure      500 {object} object "Internal server error"
+// @Deprecated
 // @Router       /api/update-teacher [put]
 func (controller *TeacherController) UpdateTeacher(c *gin.Context) {
-	var (
-		teacher entities.Teacher
-		admin   entities.Admin
-		ok      bool
-	)
+	req := requests.UpdateTeacherRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
 
-	if t, exists := c.Get("teacher"); exists {
-		teacher, ok = t.(entities.Teacher)
-	} else if a, exists := c.Get("admin"); exists {
-		admin, ok = a.(entities.Admin)
+	if !authorize(c, controller.access, entities.TeachersUpdatePermission, usecases.Resource{OwnerId: req.Id}) {
+		return
 	}
 
+	versions, ok := ifMatch(c, 1)
 	if !ok {
-		c.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
-	req := requests.UpdateTeacherRequest{}
-	err := c.ShouldBindJSON(&req)
+	data, err := controller.updateTeacherUsecase.UpdateTeacher(c, usecases.UpdateTeacherRequestDto{Id: req.Id, Version: versions[0], Fio: patch.NonZero(req.Fio), PhoneNumber: patch.NonZero(req.PhoneNumber)})
 	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+		abortWithError(c, err)
 		return
 	}
 
-	switch {
+	c.Header("ETag", entityTag(data.Teacher.Version))
+	c.JSON(http.StatusOK, data)
+}
+
+// PatchTeacher
+// @Summary      Patch teacher
+// @Description  Apply a JSON merge patch (RFC 7396) to a teacher record: absent fields are left unchanged and null clears a field. fio can't be cleared. Requires teachers.update for the teacher.
+// @Tags         teachers
+// @Security     BasicAuth
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path int true "Teacher ID"
+// @Param        teacher body requests.PatchTeacherRequest true "Changed fields"
+// @Param        If-Match header string true "ETag of the representation the change is based on, or *"
+// @Success      200 {object} entities.Teacher
+// @Failure      400 {object} object "Invalid request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Teacher not found"
+// @Failure      412 {object} object "Entity was changed since it was read"
+// @Failure      428 {object} object "If-Match header is missing"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/teachers/{id} [patch]
+func (controller *TeacherController) PatchTeacher(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
 
-	case teacher.Id > 0:
-		if teacher.Id != req.Id {
-			c.AbortWithStatus(http.StatusForbidden)
-			return
-		}
+	req := requests.PatchTeacherRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
 
-	case admin.Id > 0:
+	if !authorize(c, controller.access, entities.TeachersUpdatePermission, usecases.Resource{OwnerId: id}) {
+		return
+	}
 
-	default:
-		c.AbortWithStatus(http.StatusInternalServerError)
+	versions, ok := ifMatch(c, 1)
+	if !ok {
 		return
 	}
 
-	data, err := controller.updateTeacherUsecase.UpdateTeacher(c, usecases.UpdateTeacherRequestDto{Id: req.Id, Fio: req.Fio, PhoneNumber: req.PhoneNumber})
+	data, err := controller.updateTeacherUsecase.UpdateTeacher(c, usecases.UpdateTeacherRequestDto{Id: id, Version: versions[0], Fio: req.Fio, PhoneNumber: req.PhoneNumber})
 	if err != nil {
-		fmt.Println("failed to update teacher")
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
+	c.Header("ETag", entityTag(data.Teacher.Version))
 	c.JSON(http.StatusOK, data)
 }
 
 // DeleteTeacher
 // @Summary      Delete teacher
-// @Description  Delete teacher by ID (admin only)
+// @Description  Delete teacher by ID (requires teachers.delete).
+//
+//	mode defines what happens to the groups of the teacher:
+//	unassign (default) leaves them without a teacher, block refuses to delete
+//	a teacher who still has groups, cascade deletes the groups and their students.
+//
 // @Tags         teachers
 // @Security     BasicAuth
 // @Produce      json
-// @Param        id query int true "Teacher ID"
-// @Success      200
-// @Failure      400 {object} object "Invalid teacher ID"
+// @Param        id path int true "Teacher ID"
+// @Param        mode query string false "What to do with the groups of the teacher" Enums(unassign, block, cascade)
+// @Param        If-Match header string true "ETag of the representation the change is based on, or *"
+// @Success      204
+// @Failure      400 {object} object "Invalid teacher ID or mode"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Teacher not found"
+// @Failure      409 {object} object "The teacher still has groups"
+// @Failure      412 {object} object "Entity was changed since it was read"
+// @Failure      428 {object} object "If-Match header is missing"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/delete-teacher [delete]
+// @Router       /api/v1/teachers/{id} [delete]
 func (controller *TeacherController) DeleteTeacher(c *gin.Context) {
-	idStr := c.Query("id")
-	if idStr == "" {
-		c.AbortWithStatus(http.StatusBadRequest)
+	id, ok := idParam(c)
+	if !ok {
 		return
 	}
 
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+	versions, ok := ifMatch(c, 1)
+	if !ok {
 		return
 	}
 
-	err = controller.deleteTeacherUsecase.DeleteTeacher(c, usecases.DeleteTeacherRequestDto{Id: int(id)})
+	err := controller.deleteTeacherUsecase.DeleteTeacher(c, usecases.DeleteTeacherRequestDto{Id: id, Version: versions[0], Mode: usecases.DeleteMode(c.Query("mode"))})
 	if err != nil {
-		fmt.Println("failed to delete teacher:", err)
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.AbortWithStatus(http.StatusOK)
+	respondDeleted(c)
 }
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"backendForKeenEye/internal/controllers/requests"
+	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/internal/usecases"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"strconv"
+)
+
+var (
+	notAuthenticatedError = entities.NewDomainError(entities.UnauthorizedCode, "user not authenticated")
+	invalidBodyError      = entities.NewDomainError(entities.ValidationCode, "invalid request body")
+)
+
+// abortWithError stops the request; ErrorMiddleware renders err.
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// bindingError describes a request body that could not be bound, listing the
+// fields that failed validation in the language the client asked for.
+func bindingError(c *gin.Context, err error) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return invalidBodyError
+	}
+
+	trans := translator(c)
+	fields := make([]entities.FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		fields = append(fields, entities.FieldError{Field: fieldErr.Field(), Message: fieldErr.Translate(trans)})
+	}
+	return usecases.ValidationError.WithFields(fields...)
+}
+
+// paramError describes an invalid path or query parameter.
+func paramError(name, message string) error {
+	return usecases.ValidationError.WithFields(entities.FieldError{Field: name, Message: message})
+}
+
+// currentUser returns the user put into the context by the auth middleware.
+// If there is none the request is aborted and false is returned.
+func currentUser(c *gin.Context) (entities.User, bool) {
+	userRaw, exists := c.Get("user")
+	if !exists {
+		abortWithError(c, notAuthenticatedError)
+		return entities.User{}, false
+	}
+
+	user, ok := userRaw.(entities.User)
+	if !ok {
+		abortWithError(c, errors.New("user data is corrupted"))
+		return entities.User{}, false
+	}
+
+	return user, true
+}
+
+// authorize checks that the current user may use the permission on the
+// resource. If not the request is aborted and false is returned.
+func authorize(c *gin.Context, access AccessController, permission string, resource usecases.Resource) bool {
+	user, ok := currentUser(c)
+	if !ok {
+		return false
+	}
+
+	err := access.Authorize(c, user, permission, resource)
+	if err != nil {
+		abortWithError(c, err)
+		return false
+	}
+
+	return true
+}
+
+// idParam returns the id of the resource a request is about: the :id path
+// parameter of the /api/v1 routes or the id query parameter of the legacy
+// ones. If it is missing or malformed the request is aborted and false is
+// returned.
+func idParam(c *gin.Context) (int, bool) {
+	raw := c.Param("id")
+	if raw == "" {
+		raw = c.Query("id")
+	}
+	if raw == "" {
+		abortWithError(c, paramError("id", "is required"))
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(raw)
+	if err != nil {
+		abortWithError(c, paramError("id", "must be an integer"))
+		return 0, false
+	}
+
+	return id, true
+}
+
+// listRequest binds the paging, sorting and filtering parameters of a list
+// endpoint. If they are invalid the request is aborted and false is returned.
+func listRequest(c *gin.Context) (usecases.ListRequest, bool) {
+	req := requests.ListRequest{}
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return usecases.ListRequest{}, false
+	}
+
+	// clients of the legacy routes got every record and don't follow
+	// next_cursor, so they are paged only if they ask for a limit
+	return usecases.ListRequest{
+		Sort:      req.Sort,
+		Desc:      req.Order == "desc",
+		Limit:     req.Limit,
+		Cursor:    req.Cursor,
+		WithTotal: req.WithTotal,
+		Unpaged:   c.GetBool("legacy_route") && req.Limit == 0,
+		Filter:    entities.ListFilter{Fio: req.Fio, GroupId: req.GroupId, TeacherId: req.TeacherId, NoGroup: req.NoGroup},
+	}, true
+}
+
+// respondDeleted finishes a successful DELETE: 204 No Content, or 200 on the
+// legacy routes whose clients expect it.
+func respondDeleted(c *gin.Context) {
+	if c.GetBool("legacy_route") {
+		c.AbortWithStatus(http.StatusOK)
+		return
+	}
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+// credentialsDeliveredNote stands in for the passwords of a replayed
+// response: they are shown once and never stored.
+const credentialsDeliveredNote = "the credentials were delivered with the first response and are not repeated"
+
+// replayRedacted hands the idempotency middleware the body to store and
+// replay in place of a response that carries credentials.
+func replayRedacted(c *gin.Context, body any) {
+	c.Set("idempotent_replay", body)
+}
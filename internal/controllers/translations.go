@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	ruTranslations "github.com/go-playground/validator/v10/translations/ru"
+	"strings"
+)
+
+// translators holds the languages validation messages are available in;
+// English is the fallback.
+var translators *ut.UniversalTranslator
+
+var defaultTranslations = map[string]func(*validator.Validate, ut.Translator) error{
+	"en": enTranslations.RegisterDefaultTranslations,
+	"ru": ruTranslations.RegisterDefaultTranslations,
+}
+
+// customMessages are the messages of the rules added by RegisterValidators.
+var customMessages = map[string]map[string]string{
+	"en": {
+		"fio":            "{0} may contain only letters, spaces, hyphens, apostrophes and dots",
+		"teacher_exists": "{0} must refer to an existing teacher",
+		"group_exists":   "{0} must refer to an existing group",
+	},
+	"ru": {
+		"fio":            "{0} может содержать только буквы, пробелы, дефисы, апострофы и точки",
+		"teacher_exists": "{0} должен ссылаться на существующего преподавателя",
+		"group_exists":   "{0} должен ссылаться на существующую группу",
+	},
+}
+
+func registerTranslations(v *validator.Validate) error {
+	english := en.New()
+	translators = ut.New(english, english, ru.New())
+
+	for locale, registerDefaults := range defaultTranslations {
+		trans, _ := translators.GetTranslator(locale)
+		if err := registerDefaults(v, trans); err != nil {
+			return fmt.Errorf("register %s translations: %w", locale, err)
+		}
+
+		for tag, message := range customMessages[locale] {
+			err := v.RegisterTranslation(tag, trans, addTranslation(tag, message), translateField)
+			if err != nil {
+				return fmt.Errorf("register %s translation of %s rule: %w", locale, tag, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func addTranslation(tag, message string) validator.RegisterTranslationsFunc {
+	return func(trans ut.Translator) error {
+		return trans.Add(tag, message, true)
+	}
+}
+
+func translateField(trans ut.Translator, fieldErr validator.FieldError) string {
+	message, err := trans.T(fieldErr.Tag(), fieldErr.Field())
+	if err != nil {
+		return fieldErr.Error()
+	}
+	return message
+}
+
+// translator picks the language of validation messages from the
+// Accept-Language header, taking the first supported language listed.
+func translator(c *gin.Context) ut.Translator {
+	if translators == nil {
+		return nil
+	}
+
+	var locales []string
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		language, _, _ := strings.Cut(tag, "-")
+		if language != "" {
+			locales = append(locales, strings.ToLower(language))
+		}
+	}
+
+	trans, _ := translators.FindTranslator(locales...)
+	return trans
+}
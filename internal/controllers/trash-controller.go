@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"backendForKeenEye/internal/usecases"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type TrashController struct {
+	readDeletedUsecase    ReadDeletedUsecase
+	restoreDeletedUsecase RestoreDeletedUsecase
+	purgeDeletedUsecase   PurgeDeletedUsecase
+}
+
+func NewTrashController(
+	readDeletedUsecase ReadDeletedUsecase,
+	restoreDeletedUsecase RestoreDeletedUsecase,
+	purgeDeletedUsecase PurgeDeletedUsecase,
+) TrashController {
+	return TrashController{
+		readDeletedUsecase:    readDeletedUsecase,
+		restoreDeletedUsecase: restoreDeletedUsecase,
+		purgeDeletedUsecase:   purgeDeletedUsecase,
+	}
+}
+
+// ReadDeleted
+// @Summary      Get deleted records
+// @Description  Deleted records of one entity type, most recently deleted first (requires trash.manage)
+// @Tags         trash
+// @Security     BasicAuth
+// @Produce      json
+// @Param        type path string true "Entity type (student, teacher, admin, group)"
+// @Success      200 {object} usecases.ReadDeletedResponseDto
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Unknown entity type"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/trash/{type} [get]
+func (controller *TrashController) ReadDeleted(c *gin.Context) {
+	data, err := controller.readDeletedUsecase.ReadDeleted(c, usecases.ReadDeletedRequestDto{EntityType: c.Param("type")})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// RestoreDeleted
+// @Summary      Restore deleted record
+// @Description  Undo the deletion of a record (requires trash.manage)
+// @Tags         trash
+// @Security     BasicAuth
+// @Produce      json
+// @Param        type path string true "Entity type (student, teacher, admin, group)"
+// @Param        id path int true "Record ID"
+// @Success      200
+// @Failure      400 {object} object "Invalid record ID"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Unknown entity type or no such deleted record"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/trash/{type}/{id}/restore [post]
+func (controller *TrashController) RestoreDeleted(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		abortWithError(c, paramError("id", "must be an integer"))
+		return
+	}
+
+	err = controller.restoreDeletedUsecase.RestoreDeleted(c, usecases.RestoreDeletedRequestDto{EntityType: c.Param("type"), Id: id})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.AbortWithStatus(http.StatusOK)
+}
+
+// PurgeDeleted
+// @Summary      Purge deleted record
+// @Description  Permanently delete a record that has been deleted before, together with its account (requires trash.purge)
+// @Tags         trash
+// @Security     BasicAuth
+// @Produce      json
+// @Param        type path string true "Entity type (student, teacher, admin, group)"
+// @Param        id path int true "Record ID"
+// @Success      204
+// @Failure      400 {object} object "Invalid record ID"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Unknown entity type or no such deleted record"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/trash/{type}/{id} [delete]
+func (controller *TrashController) PurgeDeleted(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		abortWithError(c, paramError("id", "must be an integer"))
+		return
+	}
+
+	err = controller.purgeDeletedUsecase.PurgeDeleted(c, usecases.PurgeDeletedRequestDto{EntityType: c.Param("type"), Id: id})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	respondDeleted(c)
+}
@@ -4,62 +4,365 @@ import (
 	"backendForKeenEye/internal/controllers/requests"
 	"backendForKeenEye/internal/entities"
 	"backendForKeenEye/internal/usecases"
+	"backendForKeenEye/pkg/patch"
+	"backendForKeenEye/pkg/tabular"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strconv"
 )
 
+// profileCollections maps the roles having a profile to the routes serving it.
+var profileCollections = map[string]string{
+	"student": "students",
+	"teacher": "teachers",
+	"admin":   "admins",
+}
+
 type UserController struct {
-	createUserUsecase CreateUserUsecase
+	createUserUsecase     CreateUserUsecase
+	revokeSessionsUsecase RevokeSessionsUsecase
+	changePasswordUsecase ChangePasswordUsecase
+	resetPasswordUsecase  ResetPasswordUsecase
+	readMeUsecase         ReadMeUsecase
+	updateMeUsecase       UpdateMeUsecase
+	importUsersUsecase    ImportUsersUsecase
 }
 
-func NewUserController(createUserUsecase CreateUserUsecase) UserController {
-	return UserController{createUserUsecase: createUserUsecase}
+func NewUserController(createUserUsecase CreateUserUsecase, revokeSessionsUsecase RevokeSessionsUsecase, changePasswordUsecase ChangePasswordUsecase, resetPasswordUsecase ResetPasswordUsecase, readMeUsecase ReadMeUsecase, updateMeUsecase UpdateMeUsecase, importUsersUsecase ImportUsersUsecase) UserController {
+	return UserController{createUserUsecase: createUserUsecase, revokeSessionsUsecase: revokeSessionsUsecase, changePasswordUsecase: changePasswordUsecase, resetPasswordUsecase: resetPasswordUsecase, readMeUsecase: readMeUsecase, updateMeUsecase: updateMeUsecase, importUsersUsecase: importUsersUsecase}
 }
 
 // CreateUser
 // @Summary      Create user
-// @Description  Create a new user (admin only)
+// @Description  Create a new user together with its role profile (requires users.create). Logins are unique case-insensitively; group_id is accepted for students only. Only the id is returned; the new user signs in through /api/v1/login.
 // @Tags         users
 // @Security     BasicAuth
 // @Accept       json
 // @Produce      json
 // @Param        user body requests.CreateUserRequest true "User info"
-// @Success      201 {object} entities.User
+// @Success      201 {object} usecases.CreateUserResponseDto
+// @Header       201 {string} Location "URL of the created student, teacher or admin"
 // @Failure      400 {object} object "Invalid request"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      403 {object} object "Forbidden"
+// @Failure      409 {object} object "Login is already taken"
 // @Failure      500 {object} object "Internal server error"
-// @Router       /api/create-user [post]
+// @Router       /api/v1/users [post]
 func (controller *UserController) CreateUser(c *gin.Context) {
-	userRaw, exists := c.Get("user")
-	if !exists {
-		c.AbortWithStatus(http.StatusUnauthorized)
+	req := requests.CreateUserRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	data, err := controller.createUserUsecase.CreateUser(c, usecases.CreateUserRequestDto{
+		Login:    req.Login,
+		Password: req.Password,
+		Role:     req.Role,
+		Email:    req.Email,
+		Profile:  entities.Profile{Fio: req.Fio, PhoneNumber: req.PhoneNumber, GroupId: req.GroupId},
+	})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if collection, ok := profileCollections[req.Role]; ok {
+		c.Header("Location", "/api/v1/"+collection+"/"+strconv.Itoa(data.Id))
+	}
+	c.JSON(http.StatusCreated, data)
+}
+
+// ImportUsers
+// @Summary      Import users
+// @Description  Create students and teachers from a CSV or XLSX file (requires users.create). The first row names the columns: login and role are required, fio, phone and group (a group name, students only) are optional. Every user gets a one-time password that must be changed on first login. Either all rows are imported or none: the errors of every invalid row are returned, with the line of the file they are on. A dry run validates the file without creating anything. A retry with the same Idempotency-Key is answered with the created users as JSON, without their passwords.
+// @Tags         users
+// @Security     BasicAuth
+// @Accept       multipart/form-data
+// @Produce      json,text/csv
+// @Param        file formData file true "CSV or XLSX file"
+// @Param        dry_run query bool false "Only validate the file"
+// @Param        result query string false "json (default) or csv to download the logins with their passwords"
+// @Success      200 {object} usecases.ImportUsersResponseDto
+// @Success      201 {object} usecases.ImportUsersResponseDto
+// @Failure      400 {object} object "Invalid file or rows"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      413 {object} object "File is too large"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/users/import [post]
+func (controller *UserController) ImportUsers(c *gin.Context) {
+	req := requests.ImportUsersRequest{}
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			abortWithError(c, importTooLargeError)
+			return
+		}
+		abortWithError(c, paramError("file", "is required"))
 		return
 	}
+	defer file.Close()
 
-	user, ok := userRaw.(entities.User)
+	format, err := tabular.FormatOf(header.Filename)
+	if err != nil {
+		abortWithError(c, paramError("file", "must be a .csv or .xlsx file"))
+		return
+	}
+	table, err := tabular.Read(file, header.Size, format)
+	if err != nil {
+		abortWithError(c, paramError("file", "cannot be read as "+string(format)))
+		return
+	}
+	rows, err := importRows(c, table)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	data, err := controller.importUsersUsecase.ImportUsers(c, usecases.ImportUsersRequestDto{Rows: rows, DryRun: req.DryRun})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if data.DryRun {
+		status = http.StatusOK
+	}
+	replayRedacted(c, redactImport(data))
+	if req.Result == "csv" {
+		respondWithCredentials(c, status, data.Users)
+		return
+	}
+	c.JSON(status, data)
+}
+
+// RevokeSessions
+// @Summary      Sign user out everywhere
+// @Description  Invalidate every access and refresh token issued to the user (requires users.revoke_sessions)
+// @Tags         users
+// @Security     BasicAuth
+// @Param        id path int true "User ID"
+// @Success      204
+// @Failure      400 {object} object "Invalid user ID"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "User not found"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/users/{id}/revoke-sessions [post]
+func (controller *UserController) RevokeSessions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		abortWithError(c, paramError("id", "must be an integer"))
+		return
+	}
+
+	err = controller.revokeSessionsUsecase.RevokeSessions(c, usecases.RevokeSessionsRequestDto{UserId: id})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+// ChangePassword
+// @Summary      Change own password
+// @Description  Change the caller's password. The current password is required.
+// @Tags         users
+// @Security     BasicAuth
+// @Accept       json
+// @Param        passwords body requests.ChangePasswordRequest true "Current and new password"
+// @Success      204
+// @Failure      400 {object} object "Invalid request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Current password is incorrect"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/me/password [put]
+func (controller *UserController) ChangePassword(c *gin.Context) {
+	user, ok := currentUser(c)
 	if !ok {
-		c.AbortWithStatus(http.StatusInternalServerError)
 		return
 	}
 
-	if user.Role != "admin" {
-		c.AbortWithStatus(http.StatusForbidden)
+	req := requests.ChangePasswordRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
 		return
 	}
 
-	req := requests.CreateUserRequest{}
+	err = controller.changePasswordUsecase.ChangePassword(c, usecases.ChangePasswordRequestDto{UserId: user.Id, CurrentPassword: req.CurrentPassword, NewPassword: req.NewPassword})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+// ResetPassword
+// @Summary      Reset user password
+// @Description  Replace the user's password with a one-time temporary password that must be changed on next use (requires users.reset_password). A retry with the same Idempotency-Key is answered without the password.
+// @Tags         users
+// @Security     BasicAuth
+// @Produce      json
+// @Param        id path int true "User ID"
+// @Success      200 {object} usecases.ResetPasswordResponseDto
+// @Failure      400 {object} object "Invalid user ID"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "User not found"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/users/{id}/reset-password [post]
+func (controller *UserController) ResetPassword(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		abortWithError(c, paramError("id", "must be an integer"))
+		return
+	}
+
+	data, err := controller.resetPasswordUsecase.ResetPassword(c, usecases.ResetPasswordRequestDto{UserId: id})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	replayRedacted(c, redactedPasswordReset{UserId: id, Note: credentialsDeliveredNote})
+	c.JSON(http.StatusOK, data)
+}
+
+// redactedPasswordReset answers a retried password reset.
+type redactedPasswordReset struct {
+	UserId int    `json:"user_id"`
+	Note   string `json:"note"`
+}
+
+// ReadMe
+// @Summary      Get current user
+// @Description  Returns the caller's user record and role profile; students also get their group and its teacher
+// @Tags         users
+// @Security     BasicAuth
+// @Produce      json
+// @Param        If-None-Match header string false "ETag of a cached copy; 304 is returned if it is still current"
+// @Success      200 {object} usecases.ReadMeResponseDto
+// @Header       200 {string} ETag "Version of the representation"
+// @Success      304 "Not modified"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/me [get]
+func (controller *UserController) ReadMe(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	data, err := controller.readMeUsecase.ReadMe(c, usecases.ReadMeRequestDto{User: user})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	respondWithTag(c, entityTag(data.User.Version, data.ProfileVersion), data)
+}
+
+// UpdateMe
+// @Summary      Update current user
+// @Description  Update the caller's own contact details. Students may change phone number and email only.
+// @Tags         users
+// @Security     BasicAuth
+// @Accept       json
+// @Produce      json
+// @Param        user body requests.UpdateMeRequest true "Updated fields"
+// @Param        If-Match header string true "ETag of the representation (GET /api/me) the change is based on, or *"
+// @Success      200 {object} usecases.ReadMeResponseDto
+// @Failure      400 {object} object "Invalid request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Field is not editable"
+// @Failure      412 {object} object "Entity was changed since it was read"
+// @Failure      428 {object} object "If-Match header is missing"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/me [put]
+func (controller *UserController) UpdateMe(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	req := requests.UpdateMeRequest{}
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
-		c.AbortWithStatus(http.StatusBadRequest)
+		abortWithError(c, bindingError(c, err))
 		return
 	}
 
-	data, err := controller.createUserUsecase.CreateUser(c, usecases.CreateUserRequestDto{Login: req.Login, Password: req.Password, Role: req.Role})
+	versions, ok := ifMatch(c, 2)
+	if !ok {
+		return
+	}
+
+	data, err := controller.updateMeUsecase.UpdateMe(c, usecases.UpdateMeRequestDto{User: user, UserVersion: versions[0], ProfileVersion: versions[1], Fio: patch.NonZero(req.Fio), PhoneNumber: patch.NonZero(req.PhoneNumber), Email: patch.NonZero(req.Email)})
 	if err != nil {
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, data)
+	c.Header("ETag", entityTag(data.User.Version, data.ProfileVersion))
+	c.JSON(http.StatusOK, data)
+}
+
+// PatchMe
+// @Summary      Patch current user
+// @Description  Apply a JSON merge patch (RFC 7396) to the caller's own contact details: absent fields are left unchanged and null clears a field. fio can't be cleared; students may change phone number and email only.
+// @Tags         users
+// @Security     BasicAuth
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        user body requests.PatchMeRequest true "Changed fields"
+// @Param        If-Match header string true "ETag of the representation (GET /api/me) the change is based on, or *"
+// @Success      200 {object} usecases.ReadMeResponseDto
+// @Failure      400 {object} object "Invalid request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Field is not editable"
+// @Failure      412 {object} object "Entity was changed since it was read"
+// @Failure      428 {object} object "If-Match header is missing"
+// @Failure      500 {object} object "Internal server error"
+// @Router       /api/v1/me [patch]
+func (controller *UserController) PatchMe(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	req := requests.PatchMeRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		abortWithError(c, bindingError(c, err))
+		return
+	}
+
+	versions, ok := ifMatch(c, 2)
+	if !ok {
+		return
+	}
+
+	data, err := controller.updateMeUsecase.UpdateMe(c, usecases.UpdateMeRequestDto{User: user, UserVersion: versions[0], ProfileVersion: versions[1], Fio: req.Fio, PhoneNumber: req.PhoneNumber, Email: req.Email})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("ETag", entityTag(data.User.Version, data.ProfileVersion))
+	c.JSON(http.StatusOK, data)
 }
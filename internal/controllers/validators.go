@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"backendForKeenEye/pkg/patch"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// fioPattern allows Cyrillic and Latin letters separated by spaces, hyphens,
+// apostrophes and the dots of initials.
+var fioPattern = regexp.MustCompile(`^[А-Яа-яЁёA-Za-z][А-Яа-яЁёA-Za-z .'’-]*$`)
+
+// RegisterValidators adds the custom rules used by the request structs to
+// gin's validator and loads the translations of its messages.
+func RegisterValidators(references ReferenceChecker) error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected validator engine")
+	}
+
+	v.RegisterTagNameFunc(jsonFieldName)
+	v.RegisterCustomTypeFunc(patchFieldValue, patch.Field[string]{}, patch.Field[int]{})
+
+	rules := map[string]validator.FuncCtx{
+		"fio":            validateFio,
+		"teacher_exists": referenceExists(references.TeacherExists),
+		"group_exists":   referenceExists(references.GroupExists),
+	}
+	for tag, rule := range rules {
+		if err := v.RegisterValidationCtx(tag, rule); err != nil {
+			return fmt.Errorf("register %s rule: %w", tag, err)
+		}
+	}
+
+	return registerTranslations(v)
+}
+
+// jsonFieldName reports fields under the name the client sent them with, as
+// a body member or a query parameter.
+func jsonFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name != "-" && name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// patchFieldValue validates merge patch fields by their value; absent and
+// null fields have none, so only omitempty rules should be put on them.
+func patchFieldValue(field reflect.Value) any {
+	return field.Interface().(interface{ ValidationValue() any }).ValidationValue()
+}
+
+func validateFio(_ context.Context, fl validator.FieldLevel) bool {
+	return fioPattern.MatchString(fl.Field().String())
+}
+
+// referenceExists builds a rule that passes when the id refers to an existing
+// record. Lookup failures pass too: the usecase reports them properly.
+func referenceExists(exists func(ctx context.Context, id int) (bool, error)) validator.FuncCtx {
+	return func(ctx context.Context, fl validator.FieldLevel) bool {
+		found, err := exists(ctx, int(fl.Field().Int()))
+		return err != nil || found
+	}
+}
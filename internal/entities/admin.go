@@ -4,4 +4,5 @@ type Admin struct {
 	Id          int
 	Fio         string
 	PhoneNumber string
+	Version     int
 }
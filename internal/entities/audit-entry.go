@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records a single change. Before and After hold only the fields
+// that changed; either of them is empty for creations and deletions.
+type AuditEntry struct {
+	Id         int64           `json:"id"`
+	ActorId    int             `json:"actor_id"`
+	ActorRole  string          `json:"actor_role"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityId   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" swaggertype:"object"`
+	RequestId  string          `json:"request_id"`
+	Ip         string          `json:"ip"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditFilter struct {
+	ActorId    int
+	EntityType string
+	EntityId   string
+	Action     string
+	From       time.Time
+	To         time.Time
+	Limit      int
+	Offset     int
+}
+
+const (
+	AuditCreate         = "create"
+	AuditUpdate         = "update"
+	AuditDelete         = "delete"
+	AuditRestore        = "restore"
+	AuditPurge          = "purge"
+	AuditChangePassword = "change_password"
+	AuditResetPassword  = "reset_password"
+	AuditRevokeSessions = "revoke_sessions"
+	AuditUnlock         = "unlock"
+	AuditUpdateGrants   = "update_grants"
+)
+
+const (
+	AuditEntityUser         = "user"
+	AuditEntityStudent      = "student"
+	AuditEntityTeacher      = "teacher"
+	AuditEntityAdmin        = "admin"
+	AuditEntityGroup        = "group"
+	AuditEntityRole         = "role"
+	AuditEntityLoginAttempt = "login_attempt"
+)
@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// DeletedRecord is a soft-deleted student, teacher, admin or group. Name is
+// the FIO of a person or the name of a group.
+type DeletedRecord struct {
+	EntityType string    `json:"entity_type"`
+	Id         int       `json:"id"`
+	Name       string    `json:"name"`
+	DeletedAt  time.Time `json:"deleted_at"`
+}
@@ -0,0 +1,80 @@
+package entities
+
+import "errors"
+
+// ErrorCode classifies a failure independently of the transport. The HTTP
+// layer derives the response status from it.
+type ErrorCode string
+
+const (
+	NotFoundCode             ErrorCode = "not_found"
+	ConflictCode             ErrorCode = "conflict"
+	ValidationCode           ErrorCode = "validation"
+	ForbiddenCode            ErrorCode = "forbidden"
+	UnauthorizedCode         ErrorCode = "unauthorized"
+	RateLimitedCode          ErrorCode = "rate_limited"
+	PreconditionFailedCode   ErrorCode = "precondition_failed"
+	PreconditionRequiredCode ErrorCode = "precondition_required"
+	TooLargeCode             ErrorCode = "too_large"
+	NotAcceptableCode        ErrorCode = "not_acceptable"
+	UnprocessableCode        ErrorCode = "unprocessable"
+	InternalCode             ErrorCode = "internal"
+)
+
+// FieldError points at the request field a validation error is about. Row is
+// the line of an uploaded file the field is in, if any.
+type FieldError struct {
+	Row     int    `json:"row,omitempty"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// DomainError is an error the client can act on. The package level error
+// variables are DomainErrors; WithFields derives an error that still matches
+// its origin with errors.Is.
+type DomainError struct {
+	Code    ErrorCode
+	Message string
+	Fields  []FieldError
+	origin  error
+}
+
+func NewDomainError(code ErrorCode, message string) *DomainError {
+	return &DomainError{Code: code, Message: message}
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func (e *DomainError) Unwrap() error {
+	return e.origin
+}
+
+func (e *DomainError) ErrorCode() ErrorCode {
+	return e.Code
+}
+
+// WithFields returns a copy of the error that lists the offending fields.
+func (e *DomainError) WithFields(fields ...FieldError) *DomainError {
+	return &DomainError{Code: e.Code, Message: e.Message, Fields: fields, origin: e}
+}
+
+// CodeOf returns the code of the first error in the chain that has one, and
+// InternalCode if there is none.
+func CodeOf(err error) ErrorCode {
+	var coded interface{ ErrorCode() ErrorCode }
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	return InternalCode
+}
+
+// FieldsOf returns the field errors carried by err.
+func FieldsOf(err error) []FieldError {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Fields
+	}
+	return nil
+}
@@ -1,9 +1,13 @@
 package entities
 
-import (
-	"errors"
-)
-
 var (
-	InvalidRoleError = errors.New("invalid role")
+	InvalidRoleError       = NewDomainError(ValidationCode, "invalid role")
+	DuplicateLoginError    = NewDomainError(ConflictCode, "login already exists")
+	UnknownGroupError      = NewDomainError(ValidationCode, "group does not exist")
+	DuplicateRoleError     = NewDomainError(ConflictCode, "role already exists")
+	UnknownRoleError       = NewDomainError(NotFoundCode, "role does not exist")
+	UnknownPermissionError = NewDomainError(ValidationCode, "permission does not exist")
+	RecordNotFoundError    = NewDomainError(NotFoundCode, "record does not exist")
+	DuplicateRecordError   = NewDomainError(ConflictCode, "record already exists")
+	UnknownReferenceError  = NewDomainError(ValidationCode, "referenced record does not exist")
 )
@@ -4,4 +4,5 @@ type Group struct {
 	Id        int
 	Name      string
 	TeacherId int
+	Version   int
 }
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// IdempotencyKey remembers a request sent with an Idempotency-Key header by
+// its fingerprint and, once it is answered, the response to replay when the
+// client retries it. Status is zero while the request is in progress.
+type IdempotencyKey struct {
+	UserId      int
+	Key         string
+	Fingerprint string
+	Status      int
+	Header      map[string]string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func (k IdempotencyKey) IsDone() bool {
+	return k.Status != 0
+}
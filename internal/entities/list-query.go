@@ -0,0 +1,26 @@
+package entities
+
+// ListQuery selects a page of a list: the records matching Filter, ordered by
+// Sort and then by id, starting after the record named by After.
+type ListQuery struct {
+	Filter ListFilter
+	Sort   string
+	Desc   bool
+	Limit  int
+	After  *Cursor
+}
+
+// ListFilter narrows a list down. Zero values don't filter.
+type ListFilter struct {
+	Fio       string
+	GroupId   int
+	TeacherId int
+	NoGroup   bool
+}
+
+// Cursor is the position of a record in a sorted list: the value of the sort
+// field, a string or an int, and the id breaking ties.
+type Cursor struct {
+	Value any
+	Id    int
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// LoginAttempt holds the failed authentication counter for a single key, which
+// is either a login ("login:<login>") or a client address ("ip:<address>").
+type LoginAttempt struct {
+	Key         string    `json:"key"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last_failure"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+func (a LoginAttempt) IsLocked(now time.Time) bool {
+	return now.Before(a.LockedUntil)
+}
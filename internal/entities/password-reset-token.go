@@ -0,0 +1,10 @@
+package entities
+
+import "time"
+
+type PasswordResetToken struct {
+	Id        int
+	UserId    int
+	TokenHash string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,34 @@
+package entities
+
+// Permission names checked by the handlers. The set of permissions is stored
+// in the permissions table; which role holds which one is editable at runtime.
+const (
+	UsersCreatePermission         = "users.create"
+	UsersRevokeSessionsPermission = "users.revoke_sessions"
+	UsersResetPasswordPermission  = "users.reset_password"
+	LockoutsReadPermission        = "lockouts.read"
+	LockoutsUnlockPermission      = "lockouts.unlock"
+	RolesManagePermission         = "roles.manage"
+	AuditReadPermission           = "audit.read"
+	TrashManagePermission         = "trash.manage"
+	TrashPurgePermission          = "trash.purge"
+
+	StudentsReadPermission      = "students.read"
+	StudentsUpdatePermission    = "students.update"
+	StudentsDeletePermission    = "students.delete"
+	StudentsTransferPermission  = "students.transfer"
+	GroupStudentsReadPermission = "groups.students.read"
+
+	TeachersReadPermission   = "teachers.read"
+	TeachersUpdatePermission = "teachers.update"
+	TeachersDeletePermission = "teachers.delete"
+
+	AdminsReadPermission   = "admins.read"
+	AdminsUpdatePermission = "admins.update"
+	AdminsDeletePermission = "admins.delete"
+
+	GroupsCreatePermission = "groups.create"
+	GroupsReadPermission   = "groups.read"
+	GroupsUpdatePermission = "groups.update"
+	GroupsDeletePermission = "groups.delete"
+)
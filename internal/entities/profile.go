@@ -0,0 +1,9 @@
+package entities
+
+// Profile holds the role specific fields stored together with a new user.
+// GroupId is only meaningful for students.
+type Profile struct {
+	Fio         string
+	PhoneNumber string
+	GroupId     int
+}
@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+type RefreshToken struct {
+	Id         string
+	UserId     int
+	FamilyId   string
+	ExpiresAt  time.Time
+	Revoked    bool
+	ReplacedBy string
+}
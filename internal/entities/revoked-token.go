@@ -0,0 +1,9 @@
+package entities
+
+import "time"
+
+type RevokedToken struct {
+	Id        string
+	UserId    int
+	ExpiresAt time.Time
+}
@@ -0,0 +1,34 @@
+package entities
+
+// Scope limits a permission grant to a subset of resources.
+type Scope string
+
+const (
+	ScopeAll   Scope = "all"
+	ScopeGroup Scope = "group"
+	ScopeSelf  Scope = "self"
+)
+
+func (s Scope) Valid() bool {
+	switch s {
+	case ScopeAll, ScopeGroup, ScopeSelf:
+		return true
+	}
+	return false
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type Grant struct {
+	Permission string `json:"permission"`
+	Scope      Scope  `json:"scope"`
+}
+
+type Role struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Grants      []Grant `json:"grants"`
+}
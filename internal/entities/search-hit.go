@@ -0,0 +1,41 @@
+package entities
+
+const (
+	SearchHitStudent = "student"
+	SearchHitTeacher = "teacher"
+	SearchHitGroup   = "group"
+)
+
+// SearchHit is a record found by the search. Title is the fio of a person or
+// the name of a group; Rank orders hits from the most relevant.
+type SearchHit struct {
+	Type        string  `json:"type"`
+	Id          int     `json:"id"`
+	Title       string  `json:"title"`
+	Login       string  `json:"login,omitempty"`
+	PhoneNumber string  `json:"phone_number,omitempty"`
+	GroupId     int     `json:"group_id,omitempty"`
+	Rank        float64 `json:"rank"`
+}
+
+// SearchQuery is a normalized search text. Digits holds the digits of the
+// text when it looks like a phone number and is empty otherwise.
+type SearchQuery struct {
+	Text       string
+	Digits     string
+	Limit      int
+	Visibility Visibility
+}
+
+// Visibility is the part of a table a user may read: every record, or the
+// ones owned by OwnerId and the ones in GroupIds.
+type Visibility struct {
+	All      bool
+	OwnerId  int
+	GroupIds []int
+}
+
+// None reports whether nothing is visible.
+func (v Visibility) None() bool {
+	return !v.All && v.OwnerId == 0 && len(v.GroupIds) == 0
+}
@@ -5,4 +5,5 @@ type Student struct {
 	Fio         string
 	PhoneNumber string
 	GroupId     int
+	Version     int
 }
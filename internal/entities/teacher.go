@@ -4,4 +4,5 @@ type Teacher struct {
 	Id          int
 	Fio         string
 	PhoneNumber string
+	Version     int
 }
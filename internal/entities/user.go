@@ -1,27 +1,22 @@
 package entities
 
-var allowedRoles = []string{"admin", "student", "teacher"}
-
 type User struct {
-	Id       int
-	Login    string
-	Password string
-	Salt     string
-	Role     string
+	Id                 int
+	Login              string
+	Password           string `json:"-"`
+	Salt               string `json:"-"`
+	Role               string
+	Email              string
+	TokenVersion       int `json:"-"`
+	MustChangePassword bool
+	Version            int
 }
 
+// Validate only checks that a role is set; whether it exists is decided by
+// the roles table, so that new roles can be added without code changes.
 func (a User) Validate() (bool, error) {
-	if validateRole(a.Role) == false {
+	if a.Role == "" {
 		return false, InvalidRoleError
 	}
 	return true, nil
 }
-
-func validateRole(role string) bool {
-	for _, r := range allowedRoles {
-		if role == r {
-			return true
-		}
-	}
-	return false
-}
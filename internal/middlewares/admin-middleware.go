@@ -1,9 +0,0 @@
-package middlewares
-
-import (
-	"github.com/gin-gonic/gin"
-)
-
-func AdminMiddleware() gin.HandlerFunc {
-	return checkingRoles("admin")
-}
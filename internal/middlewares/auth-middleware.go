@@ -1,12 +1,10 @@
 package middlewares
 
 import (
-	"backendForKeenEye/internal/entities"
 	"backendForKeenEye/internal/usecases"
 	"context"
 	"encoding/base64"
 	"github.com/gin-gonic/gin"
-	"net/http"
 	"strings"
 )
 
@@ -21,7 +19,7 @@ func AuthMiddleware(ctx context.Context, authService *usecases.AuthService) gin.
 			JWTAuthMiddleware(ctx, authService)(c)
 
 		default:
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unsupported or missing Authorization header"})
+			abortWithError(c, missingAuthorizationError)
 		}
 	}
 }
@@ -31,25 +29,32 @@ func BasicAuthMiddleware(ctx context.Context, authService *usecases.AuthService)
 		auth := strings.TrimPrefix(c.GetHeader("Authorization"), "Basic ")
 		decoded, err := base64.StdEncoding.DecodeString(auth)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid base64 encoding"})
+			abortWithError(c, invalidBasicEncodingError)
 			return
 		}
 
 		parts := strings.SplitN(string(decoded), ":", 2)
 		if len(parts) != 2 {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Basic auth format"})
+			abortWithError(c, invalidBasicFormatError)
 			return
 		}
 
 		login, password := parts[0], parts[1]
-		user, err := authService.GetUserByLoginAndPassword(ctx, login, password)
+		user, err := authService.Authenticate(ctx, login, password, c.ClientIP())
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Basic credentials"})
+			if abortThrottled(c, err) {
+				return
+			}
+			abortWithError(c, invalidBasicCredentialsError)
+			return
+		}
+
+		if !passwordChangeAllowed(c, user) {
 			return
 		}
 
 		c.Set("user", user)
-		AttachUserRoleData(ctx, c, authService, user)
+		setActor(c, user)
 		c.Next()
 	}
 }
@@ -57,31 +62,21 @@ func BasicAuthMiddleware(ctx context.Context, authService *usecases.AuthService)
 func JWTAuthMiddleware(ctx context.Context, authService *usecases.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
-		user, err := authService.GetUserByAccessToken(c.Request.Context(), token)
+		user, err := authService.AuthenticateToken(c.Request.Context(), token, c.ClientIP())
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid JWT token"})
+			if abortThrottled(c, err) {
+				return
+			}
+			abortWithError(c, invalidTokenError)
+			return
+		}
+
+		if !passwordChangeAllowed(c, user) {
 			return
 		}
 
 		c.Set("user", user)
-		AttachUserRoleData(ctx, c, authService, user)
+		setActor(c, user)
 		c.Next()
 	}
 }
-
-func AttachUserRoleData(ctx context.Context, c *gin.Context, authService *usecases.AuthService, user entities.User) {
-	switch user.Role {
-	case "student":
-		if student, err := authService.GetStudentById(ctx, user.Id); err == nil {
-			c.Set("student", student)
-		}
-	case "teacher":
-		if teacher, err := authService.GetTeacherById(ctx, user.Id); err == nil {
-			c.Set("teacher", teacher)
-		}
-	case "admin":
-		if admin, err := authService.GetAdminById(ctx, user.Id); err == nil {
-			c.Set("admin", admin)
-		}
-	}
-}
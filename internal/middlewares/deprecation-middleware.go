@@ -0,0 +1,50 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DeprecationMiddleware serves a legacy route kept as an alias of an /api/v1
+// route. Responses are marked as deprecated since deprecatedAt (RFC 9745) and
+// carry the date the route is removed (RFC 8594) and, when it can be built, a
+// link to the successor. Parameter segments of successor, like :id, are filled
+// from the path or query parameters of the legacy request.
+func DeprecationMiddleware(deprecatedAt, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+
+	return func(c *gin.Context) {
+		c.Set("legacy_route", true)
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetDate)
+		if link, ok := successorLink(c, successor); ok {
+			c.Header("Link", "<"+link+`>; rel="successor-version"`)
+		}
+		c.Next()
+	}
+}
+
+func successorLink(c *gin.Context, successor string) (string, bool) {
+	segments := strings.Split(successor, "/")
+	for i, segment := range segments {
+		name, ok := strings.CutPrefix(segment, ":")
+		if !ok {
+			continue
+		}
+
+		value := c.Param(name)
+		if value == "" {
+			value = c.Query(name)
+		}
+		if value == "" {
+			return "", false
+		}
+		segments[i] = url.PathEscape(value)
+	}
+	return strings.Join(segments, "/"), true
+}
@@ -0,0 +1,98 @@
+package middlewares
+
+import (
+	"backendForKeenEye/internal/entities"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	problemRenderedKey = "problem_rendered"
+)
+
+var statusByCode = map[entities.ErrorCode]int{
+	entities.NotFoundCode:             http.StatusNotFound,
+	entities.ConflictCode:             http.StatusConflict,
+	entities.ValidationCode:           http.StatusBadRequest,
+	entities.ForbiddenCode:            http.StatusForbidden,
+	entities.UnauthorizedCode:         http.StatusUnauthorized,
+	entities.RateLimitedCode:          http.StatusTooManyRequests,
+	entities.PreconditionFailedCode:   http.StatusPreconditionFailed,
+	entities.PreconditionRequiredCode: http.StatusPreconditionRequired,
+	entities.TooLargeCode:             http.StatusRequestEntityTooLarge,
+	entities.NotAcceptableCode:        http.StatusNotAcceptable,
+	entities.UnprocessableCode:        http.StatusUnprocessableEntity,
+	entities.InternalCode:             http.StatusInternalServerError,
+}
+
+// Problem is an RFC 7807 problem document, extended with the error code, the
+// offending fields and the request id.
+type Problem struct {
+	Type      string                `json:"type"`
+	Title     string                `json:"title"`
+	Status    int                   `json:"status"`
+	Detail    string                `json:"detail,omitempty"`
+	Instance  string                `json:"instance,omitempty"`
+	Code      entities.ErrorCode    `json:"code"`
+	Errors    []entities.FieldError `json:"errors,omitempty"`
+	RequestId string                `json:"request_id,omitempty"`
+}
+
+// ErrorMiddleware renders the last error attached to the request with
+// c.Error as a problem document. The status follows the error code; errors
+// without a code are logged and reported without details.
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.GetBool(problemRenderedKey) {
+			return
+		}
+		err := c.Errors.Last().Err
+		if c.Writer.Written() {
+			// the response was under way, e.g. a file being streamed
+			fmt.Println("request failed after responding:", c.Request.Method, c.Request.URL.Path, err)
+			return
+		}
+
+		respondWithProblem(c, err)
+	}
+}
+
+// respondWithProblem writes err as a problem document. Middlewares that need
+// the response before ErrorMiddleware gets to it render it themselves.
+func respondWithProblem(c *gin.Context, err error) {
+	code := entities.CodeOf(err)
+	status, ok := statusByCode[code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    err.Error(),
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		Errors:    entities.FieldsOf(err),
+		RequestId: c.Writer.Header().Get(requestIdHeader),
+	}
+	if status == http.StatusInternalServerError {
+		fmt.Println("request failed:", c.Request.Method, c.Request.URL.Path, err)
+		problem.Detail = ""
+	}
+
+	var retry interface{ RetryAfterSeconds() int }
+	if errors.As(err, &retry) {
+		c.Header("Retry-After", strconv.Itoa(retry.RetryAfterSeconds()))
+	}
+
+	c.Set(problemRenderedKey, true)
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(status, problem)
+}
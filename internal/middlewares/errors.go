@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"backendForKeenEye/internal/entities"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	missingAuthorizationError    = entities.NewDomainError(entities.UnauthorizedCode, "unsupported or missing Authorization header")
+	invalidBasicEncodingError    = entities.NewDomainError(entities.UnauthorizedCode, "invalid base64 encoding")
+	invalidBasicFormatError      = entities.NewDomainError(entities.UnauthorizedCode, "invalid Basic auth format")
+	invalidBasicCredentialsError = entities.NewDomainError(entities.UnauthorizedCode, "invalid Basic credentials")
+	invalidTokenError            = entities.NewDomainError(entities.UnauthorizedCode, "invalid JWT token")
+	notAuthenticatedError        = entities.NewDomainError(entities.UnauthorizedCode, "user not authenticated")
+	passwordChangeRequiredError  = entities.NewDomainError(entities.ForbiddenCode, "password change required")
+	invalidIdempotencyKeyError   = entities.NewDomainError(entities.ValidationCode, "Idempotency-Key must be 1 to 255 printable ASCII characters")
+	idempotentBodyTooLargeError  = entities.NewDomainError(entities.TooLargeCode, "request body is too large to be sent with an Idempotency-Key")
+)
+
+// abortWithError stops the request; ErrorMiddleware renders err.
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
@@ -0,0 +1,175 @@
+package middlewares
+
+import (
+	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/internal/usecases"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	replayedHeader       = "Idempotent-Replayed"
+
+	// maxIdempotentBodySize bounds the body read into memory to fingerprint
+	// it; it is above the largest import file.
+	maxIdempotentBodySize = 16 << 20
+)
+
+var idempotencyKeyPattern = regexp.MustCompile(`^[\x21-\x7E]{1,255}$`)
+
+// storedHeaders are the response headers replayed along with the body.
+var storedHeaders = []string{"Content-Type", "Content-Disposition", "ETag", "Location", "Retry-After"}
+
+// IdempotencyMiddleware honors the Idempotency-Key header of the requests of
+// authenticated users. The first request with a key runs and its response is
+// stored; a retry with the same key, method, URL, If-Match and body gets the
+// stored response with an Idempotent-Replayed header. Reusing the key for a
+// different request is answered with 422, and a retry while the first request
+// is still running with 409. Server errors aren't stored, so the client may
+// retry them. Requests without the header run as usual. A handler whose
+// response carries credentials sets idempotent_replay to a redacted body,
+// which is stored and replayed as JSON instead.
+func IdempotencyMiddleware(idempotency *usecases.Idempotency) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if !idempotencyKeyPattern.MatchString(key) {
+			abortWithError(c, invalidIdempotencyKeyError)
+			return
+		}
+
+		userRaw, exists := c.Get("user")
+		if !exists {
+			abortWithError(c, notAuthenticatedError)
+			return
+		}
+		user, ok := userRaw.(entities.User)
+		if !ok {
+			abortWithError(c, errors.New("user data is corrupted"))
+			return
+		}
+
+		fingerprint, err := requestFingerprint(c)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		record, replay, err := idempotency.Begin(c, user.Id, key, fingerprint)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if replay {
+			for name, value := range record.Header {
+				c.Header(name, value)
+			}
+			c.Header(replayedHeader, "true")
+			c.Status(record.Status)
+			_, _ = c.Writer.Write(record.Body)
+			c.Abort()
+			return
+		}
+
+		// the response is stored even if the client has gone away
+		ctx := context.WithoutCancel(c.Request.Context())
+		stored := false
+		defer func() {
+			if !stored {
+				if err := idempotency.Release(ctx, user.Id, key); err != nil {
+					fmt.Println("failed to release idempotency key:", err)
+				}
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if len(c.Errors) > 0 && !recorder.Written() {
+			respondWithProblem(c, c.Errors.Last().Err)
+		}
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		record.Status = recorder.Status()
+		record.Body = recorder.body.Bytes()
+		record.Header = make(map[string]string)
+		for _, name := range storedHeaders {
+			if value := recorder.Header().Get(name); value != "" {
+				record.Header[name] = value
+			}
+		}
+		if redacted, ok := c.Get("idempotent_replay"); ok {
+			record.Body, err = json.Marshal(redacted)
+			if err != nil {
+				fmt.Println("failed to encode idempotent response:", err)
+				return
+			}
+			record.Header = map[string]string{"Content-Type": "application/json; charset=utf-8"}
+		}
+
+		err = idempotency.Complete(ctx, record)
+		if err != nil {
+			fmt.Println("failed to store idempotent response:", err)
+			return
+		}
+		stored = true
+	}
+}
+
+// requestFingerprint hashes what makes two requests the same: the method,
+// the URL, the version the change is based on and the body. The body is
+// read and put back for the handler.
+func requestFingerprint(c *gin.Context) (string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			return "", entities.NewDomainError(entities.ValidationCode, "failed to read request body")
+		}
+		if len(body) > maxIdempotentBodySize {
+			return "", idempotentBodyTooLargeError
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	hash := sha256.New()
+	for _, part := range []string{c.Request.Method, c.Request.URL.RequestURI(), c.GetHeader("If-Match")} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// responseRecorder keeps a copy of the response body.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"backendForKeenEye/internal/entities"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// passwordChangeRoutes are the only routes a user with a temporary password
+// may reach until the password is changed.
+var passwordChangeRoutes = map[string]struct{}{
+	http.MethodPut + " /api/v1/me/password": {},
+	http.MethodPost + " /api/v1/logout":     {},
+	http.MethodPut + " /api/me/password":    {},
+	http.MethodPost + " /api/logout":        {},
+}
+
+func passwordChangeAllowed(c *gin.Context, user entities.User) bool {
+	if !user.MustChangePassword {
+		return true
+	}
+
+	if _, ok := passwordChangeRoutes[c.Request.Method+" "+c.FullPath()]; ok {
+		return true
+	}
+
+	abortWithError(c, passwordChangeRequiredError)
+	return false
+}
@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/internal/usecases"
+	"errors"
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionMiddleware lets the request through only if the user's role holds
+// the permission for every resource. Handlers whose access depends on the
+// record being touched check the scope themselves.
+func PermissionMiddleware(access *usecases.AccessControl, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRaw, exists := c.Get("user")
+		if !exists {
+			abortWithError(c, notAuthenticatedError)
+			return
+		}
+
+		user, ok := userRaw.(entities.User)
+		if !ok {
+			abortWithError(c, errors.New("user data is corrupted"))
+			return
+		}
+
+		err := access.Authorize(c, user, permission, usecases.Resource{})
+		if err != nil {
+			if errors.Is(err, usecases.AccessDeniedError) {
+				err = entities.NewDomainError(entities.ForbiddenCode, "access denied: missing permission "+permission)
+			}
+			abortWithError(c, err)
+			return
+		}
+
+		c.Next()
+	}
+}
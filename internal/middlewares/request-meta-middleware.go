@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/internal/usecases"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"regexp"
+)
+
+const requestIdHeader = "X-Request-Id"
+
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestMetaMiddleware puts the request id and the client address into the
+// request context for the audit log. A well-formed X-Request-Id sent by a
+// proxy is kept, otherwise a new id is generated; it is echoed back either way.
+func RequestMetaMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(requestIdHeader)
+		if !requestIdPattern.MatchString(requestId) {
+			requestId = newRequestId()
+		}
+		c.Header(requestIdHeader, requestId)
+
+		ctx := usecases.ContextWithRequestMeta(c.Request.Context(), usecases.RequestMeta{RequestId: requestId, Ip: c.ClientIP()})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// setActor records the authenticated user in the request metadata.
+func setActor(c *gin.Context, user entities.User) {
+	meta := usecases.RequestMetaFromContext(c.Request.Context())
+	meta.UserId = user.Id
+	meta.Role = user.Role
+	c.Request = c.Request.WithContext(usecases.ContextWithRequestMeta(c.Request.Context(), meta))
+}
+
+func newRequestId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"backendForKeenEye/internal/usecases"
+	"errors"
+	"github.com/gin-gonic/gin"
+)
+
+// abortThrottled aborts the request with err if it says that the client has to
+// wait before trying to authenticate again; ErrorMiddleware answers 429 with a
+// Retry-After header.
+func abortThrottled(c *gin.Context, err error) bool {
+	var throttled *usecases.ThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+
+	abortWithError(c, err)
+	return true
+}
@@ -17,73 +17,99 @@ func NewAdminRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderTyp
 	return &AdminRepository{pool: pool, builder: builder}
 }
 
+// ReadById returns the admin unless it is deleted.
 func (repo *AdminRepository) ReadById(ctx context.Context, id int) (entities.Admin, error) {
+	return repo.readById(ctx, id, false)
+}
+
+func (repo *AdminRepository) ReadByIdIncludingDeleted(ctx context.Context, id int) (entities.Admin, error) {
+	return repo.readById(ctx, id, true)
+}
+
+func (repo *AdminRepository) readById(ctx context.Context, id int, includeDeleted bool) (entities.Admin, error) {
 	var fio, phoneNumber sql.NullString
+	var version int
 
-	sql, args, err := repo.builder.
-		Select("fio", "phone_number").
+	query := repo.builder.
+		Select("fio", "phone_number", "version").
 		From("admins").
-		Where(squirrel.Eq{"id": id}).
-		ToSql()
+		Where(squirrel.Eq{"id": id})
+	if !includeDeleted {
+		query = query.Where(squirrel.Eq{"is_deleted": false})
+	}
 
+	sql, args, err := query.ToSql()
 	if err != nil {
 		return entities.Admin{}, SqlStatementError
 	}
 
-	err = repo.pool.QueryRow(ctx, sql, args...).Scan(
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(
 		&fio,
 		&phoneNumber,
+		&version,
 	)
 	if err != nil {
-		return entities.Admin{}, SqlReadError
+		return entities.Admin{}, pgError(err, SqlReadError)
 	}
 
-	return entities.Admin{Id: id, Fio: validateString(fio), PhoneNumber: validateString(phoneNumber)}, nil
+	return entities.Admin{Id: id, Fio: validateString(fio), PhoneNumber: validateString(phoneNumber), Version: version}, nil
 }
 
-func (repo *AdminRepository) Update(ctx context.Context, id int, updates map[string]any) (entities.Admin, error) {
+// Update changes the admin if it is still at version.
+func (repo *AdminRepository) Update(ctx context.Context, id int, version int, updates map[string]any) (entities.Admin, error) {
 	var fio, phoneNumber sql.NullString
-	sql, args, err := repo.builder.
+	var newVersion int
+
+	query := repo.builder.
 		Update("admins").
 		Where(squirrel.Eq{"id": id}).
-		SetMap(updates).
-		Suffix("RETURNING fio, phone_number").
+		SetMap(updates)
+
+	sql, args, err := bumpVersion(query, version).
+		Suffix("RETURNING fio, phone_number, version").
 		ToSql()
 
 	if err != nil {
 		return entities.Admin{}, SqlStatementError
 	}
 
-	err = repo.pool.QueryRow(ctx, sql, args...).Scan(
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(
 		&fio,
 		&phoneNumber,
+		&newVersion,
 	)
 
 	if err != nil {
-		return entities.Admin{}, SqlUpdateError
+		return entities.Admin{}, pgError(err, SqlUpdateError)
 	}
 
 	return entities.Admin{
 		Id:          id,
 		Fio:         validateString(fio),
 		PhoneNumber: validateString(phoneNumber),
+		Version:     newVersion,
 	}, nil
 }
 
-func (repo *AdminRepository) SoftDelete(ctx context.Context, id int) error {
-	sql, args, err := repo.builder.
+// SoftDelete marks the admin deleted if it is still at version.
+func (repo *AdminRepository) SoftDelete(ctx context.Context, id int, version int) error {
+	query := repo.builder.
 		Update("admins").
 		Where(squirrel.Eq{"id": id}).
 		Set("is_deleted", true).
-		ToSql()
+		Set("deleted_at", squirrel.Expr("now()"))
 
+	sql, args, err := bumpVersion(query, version).ToSql()
 	if err != nil {
 		return SqlStatementError
 	}
 
-	_, err = repo.pool.Exec(ctx, sql, args...)
+	tag, err := executor(ctx, repo.pool).Exec(ctx, sql, args...)
 	if err != nil {
-		return SqlDeleteError
+		return pgError(err, SqlDeleteError)
+	}
+	if tag.RowsAffected() == 0 {
+		return entities.RecordNotFoundError
 	}
 
 	return nil
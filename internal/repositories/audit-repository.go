@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepository struct {
+	pool    *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewAuditRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderType) *AuditRepository {
+	return &AuditRepository{pool: pool, builder: builder}
+}
+
+func (repo *AuditRepository) Create(ctx context.Context, entry entities.AuditEntry) error {
+	sql, args, err := repo.builder.
+		Insert("audit_log").
+		Columns("actor_id", "actor_role", "action", "entity_type", "entity_id", "before", "after", "request_id", "ip").
+		Values(
+			nullableInt(entry.ActorId),
+			nullableString(entry.ActorRole),
+			entry.Action,
+			entry.EntityType,
+			entry.EntityId,
+			nullableJSON(entry.Before),
+			nullableJSON(entry.After),
+			nullableString(entry.RequestId),
+			nullableString(entry.Ip),
+		).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlInsertError)
+	}
+
+	return nil
+}
+
+// Read returns the entries matching the filter, newest first.
+func (repo *AuditRepository) Read(ctx context.Context, filter entities.AuditFilter) ([]entities.AuditEntry, error) {
+	var actorId sql.NullInt32
+	var actorRole, requestId, ip sql.NullString
+	query := repo.builder.
+		Select("id", "actor_id", "actor_role", "action", "entity_type", "entity_id", "before", "after", "request_id", "ip", "created_at").
+		From("audit_log").
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset))
+
+	if filter.ActorId != 0 {
+		query = query.Where(squirrel.Eq{"actor_id": filter.ActorId})
+	}
+	if filter.EntityType != "" {
+		query = query.Where(squirrel.Eq{"entity_type": filter.EntityType})
+	}
+	if filter.EntityId != "" {
+		query = query.Where(squirrel.Eq{"entity_id": filter.EntityId})
+	}
+	if filter.Action != "" {
+		query = query.Where(squirrel.Eq{"action": filter.Action})
+	}
+	if !filter.From.IsZero() {
+		query = query.Where(squirrel.GtOrEq{"created_at": filter.From})
+	}
+	if !filter.To.IsZero() {
+		query = query.Where(squirrel.Lt{"created_at": filter.To})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, SqlStatementError
+	}
+
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, pgError(err, SqlReadError)
+	}
+	defer rows.Close()
+
+	entries := []entities.AuditEntry{}
+	for rows.Next() {
+		var entry entities.AuditEntry
+		err = rows.Scan(
+			&entry.Id,
+			&actorId,
+			&actorRole,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityId,
+			&entry.Before,
+			&entry.After,
+			&requestId,
+			&ip,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, SqlScanError
+		}
+
+		entry.ActorId = validateInt(actorId)
+		entry.ActorRole = validateString(actorRole)
+		entry.RequestId = validateString(requestId)
+		entry.Ip = validateString(ip)
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
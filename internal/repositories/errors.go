@@ -9,4 +9,5 @@ var (
 	SqlUpdateError    = errors.New("failed to update entity")
 	SqlDeleteError    = errors.New("failed to delete entity")
 	SqlScanError      = errors.New("failed to scan entities")
+	SqlConflictError  = errors.New("entity was changed concurrently")
 )
@@ -38,126 +38,327 @@ func (repo *GroupRepository) Create(ctx context.Context, group entities.Group) (
 	}
 
 	var newID int
-	err = repo.pool.QueryRow(ctx, sql, args...).Scan(&newID)
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(&newID)
 	if err != nil {
-		return 0, SqlInsertError
+		return 0, pgError(err, SqlInsertError)
 	}
 
 	return newID, nil
 }
 
-func (repo *GroupRepository) Read(ctx context.Context) ([]entities.Group, error) {
-	var id int
+var groupSortColumns = sortColumns{
+	"id":         "id",
+	"name":       "coalesce(name, '')",
+	"teacher_id": "coalesce(teacher_id, 0)",
+}
+
+// Read returns a page of the groups that aren't deleted.
+func (repo *GroupRepository) Read(ctx context.Context, list entities.ListQuery) ([]entities.Group, error) {
+	var groups []entities.Group
+	err := repo.Each(ctx, list, func(group entities.Group) error {
+		groups = append(groups, group)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// Each calls fn with the groups that aren't deleted in the order of list as
+// they are read, without holding them all in memory. It stops at the first
+// error fn returns.
+func (repo *GroupRepository) Each(ctx context.Context, list entities.ListQuery, fn func(entities.Group) error) error {
+	var id, version int
 	var name sql.NullString
 	var teacherId sql.NullInt32
-	sql, args, err := repo.builder.
-		Select("id", "name", "teacher_id").
+	query := repo.builder.
+		Select("id", "name", "teacher_id", "version").
 		From("groups").
 		Where(squirrel.Eq{"is_deleted": false}).
-		ToSql()
+		Where(groupFilter(list.Filter))
 
+	query, err := page(query, list, groupSortColumns)
 	if err != nil {
-		return nil, SqlStatementError
+		return err
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return SqlStatementError
 	}
 
-	rows, err := repo.pool.Query(ctx, sql, args...)
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
 	if err != nil {
-		return nil, SqlReadError
+		return pgError(err, SqlReadError)
 	}
 	defer rows.Close()
 
-	var groups []entities.Group
 	for rows.Next() {
 		err = rows.Scan(
 			&id,
 			&name,
 			&teacherId,
+			&version,
 		)
 		if err != nil {
-			return nil, SqlScanError
+			return SqlScanError
 		}
 
 		group := entities.Group{
 			Id:        id,
 			Name:      validateString(name),
 			TeacherId: validateInt(teacherId),
+			Version:   version,
+		}
+
+		if err = fn(group); err != nil {
+			return err
 		}
-		groups = append(groups, group)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows error: %w", err)
+		return fmt.Errorf("rows error: %w", err)
 	}
 
-	return groups, nil
+	return nil
 }
 
+// Count returns the number of groups that aren't deleted and match filter.
+func (repo *GroupRepository) Count(ctx context.Context, filter entities.ListFilter) (int, error) {
+	sql, args, err := repo.builder.
+		Select("count(*)").
+		From("groups").
+		Where(squirrel.Eq{"is_deleted": false}).
+		Where(groupFilter(filter)).
+		ToSql()
+
+	if err != nil {
+		return 0, SqlStatementError
+	}
+
+	var count int
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(&count)
+	if err != nil {
+		return 0, pgError(err, SqlReadError)
+	}
+
+	return count, nil
+}
+
+func groupFilter(filter entities.ListFilter) squirrel.And {
+	conditions := squirrel.And{}
+	if filter.TeacherId != 0 {
+		conditions = append(conditions, squirrel.Eq{"teacher_id": filter.TeacherId})
+	}
+	return conditions
+}
+
+// ReadById returns the group unless it is deleted.
 func (repo *GroupRepository) ReadById(ctx context.Context, id int) (entities.Group, error) {
+	return repo.readById(ctx, id, false)
+}
+
+func (repo *GroupRepository) ReadByIdIncludingDeleted(ctx context.Context, id int) (entities.Group, error) {
+	return repo.readById(ctx, id, true)
+}
+
+func (repo *GroupRepository) readById(ctx context.Context, id int, includeDeleted bool) (entities.Group, error) {
 	var name sql.NullString
 	var teacherId sql.NullInt32
+	var version int
 
-	sql, args, err := repo.builder.
-		Select("name", "teacher_id").
+	query := repo.builder.
+		Select("name", "teacher_id", "version").
 		From("groups").
-		Where(squirrel.Eq{"id": id}).
-		ToSql()
+		Where(squirrel.Eq{"id": id})
+	if !includeDeleted {
+		query = query.Where(squirrel.Eq{"is_deleted": false})
+	}
 
+	sql, args, err := query.ToSql()
 	if err != nil {
 		return entities.Group{}, SqlStatementError
 	}
 
-	err = repo.pool.QueryRow(ctx, sql, args...).Scan(
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(
 		&name,
 		&teacherId,
+		&version,
 	)
 	if err != nil {
-		return entities.Group{}, SqlReadError
+		return entities.Group{}, pgError(err, SqlReadError)
 	}
 
-	return entities.Group{Id: id, Name: validateString(name), TeacherId: validateInt(teacherId)}, nil
+	return entities.Group{Id: id, Name: validateString(name), TeacherId: validateInt(teacherId), Version: version}, nil
 }
 
-func (repo *GroupRepository) Update(ctx context.Context, id int, updates map[string]any) (entities.Group, error) {
+// ReadByTeacherId returns the groups taught by the teacher that are not
+// deleted.
+func (repo *GroupRepository) ReadByTeacherId(ctx context.Context, teacherId int) ([]entities.Group, error) {
+	var id, version int
 	var name sql.NullString
-	var teacherId sql.NullInt32
 	sql, args, err := repo.builder.
+		Select("id", "name", "version").
+		From("groups").
+		Where(squirrel.Eq{"teacher_id": teacherId, "is_deleted": false}).
+		ToSql()
+
+	if err != nil {
+		return nil, SqlStatementError
+	}
+
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, pgError(err, SqlReadError)
+	}
+	defer rows.Close()
+
+	var groups []entities.Group
+	for rows.Next() {
+		err = rows.Scan(&id, &name, &version)
+		if err != nil {
+			return nil, SqlScanError
+		}
+
+		groups = append(groups, entities.Group{Id: id, Name: validateString(name), TeacherId: teacherId, Version: version})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return groups, nil
+}
+
+// ReadIdsByNames looks groups that aren't deleted up by name
+// case-insensitively. The ids are keyed by the names as given; names shared
+// by several groups have several ids.
+func (repo *GroupRepository) ReadIdsByNames(ctx context.Context, names []string) (map[string][]int, error) {
+	var id int
+	var name string
+	sql, args, err := repo.builder.
+		Select("groups.id", "requested.name").
+		From("groups").
+		Join("unnest(?::text[]) AS requested(name) ON lower(groups.name) = lower(requested.name)", names).
+		Where(squirrel.Eq{"groups.is_deleted": false}).
+		OrderBy("groups.id").
+		ToSql()
+
+	if err != nil {
+		return nil, SqlStatementError
+	}
+
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, pgError(err, SqlReadError)
+	}
+	defer rows.Close()
+
+	ids := make(map[string][]int)
+	for rows.Next() {
+		err = rows.Scan(&id, &name)
+		if err != nil {
+			return nil, SqlScanError
+		}
+
+		ids[name] = append(ids[name], id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Update changes the group if it is still at version.
+func (repo *GroupRepository) Update(ctx context.Context, id int, version int, updates map[string]any) (entities.Group, error) {
+	var name sql.NullString
+	var teacherId sql.NullInt32
+	var newVersion int
+
+	query := repo.builder.
 		Update("groups").
 		Where(squirrel.Eq{"id": id}).
-		SetMap(updates).
-		Suffix("RETURNING name, teacher_id").
+		SetMap(updates)
+
+	sql, args, err := bumpVersion(query, version).
+		Suffix("RETURNING name, teacher_id, version").
 		ToSql()
 
 	if err != nil {
 		return entities.Group{}, SqlStatementError
 	}
 
-	err = repo.pool.QueryRow(ctx, sql, args...).Scan(
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(
 		&name,
 		&teacherId,
+		&newVersion,
 	)
 
 	if err != nil {
-		return entities.Group{}, SqlUpdateError
+		return entities.Group{}, pgError(err, SqlUpdateError)
 	}
 
-	return entities.Group{Id: id, Name: validateString(name), TeacherId: validateInt(teacherId)}, nil
+	return entities.Group{Id: id, Name: validateString(name), TeacherId: validateInt(teacherId), Version: newVersion}, nil
 }
 
-func (repo *GroupRepository) SoftDelete(ctx context.Context, id int) error {
-	sql, args, err := repo.builder.
+// SoftDelete marks the group deleted if it is still at version.
+func (repo *GroupRepository) SoftDelete(ctx context.Context, id int, version int) error {
+	query := repo.builder.
 		Update("groups").
 		Where(squirrel.Eq{"id": id}).
 		Set("is_deleted", true).
-		ToSql()
+		Set("deleted_at", squirrel.Expr("now()"))
 
+	sql, args, err := bumpVersion(query, version).ToSql()
 	if err != nil {
 		return SqlStatementError
 	}
 
-	_, err = repo.pool.Exec(ctx, sql, args...)
+	tag, err := executor(ctx, repo.pool).Exec(ctx, sql, args...)
 	if err != nil {
-		return SqlDeleteError
+		return pgError(err, SqlDeleteError)
+	}
+	if tag.RowsAffected() == 0 {
+		return entities.RecordNotFoundError
 	}
 
 	return nil
 }
+
+// ReadIdsByMember returns the groups the user belongs to: the ones they teach
+// and the one they study in.
+func (repo *GroupRepository) ReadIdsByMember(ctx context.Context, userId int) ([]int, error) {
+	var id int
+	sql, args, err := repo.builder.
+		Select("id").
+		From("groups").
+		Where(squirrel.Eq{"teacher_id": userId, "is_deleted": false}).
+		Suffix("UNION SELECT group_id FROM students WHERE id = ? AND group_id IS NOT NULL AND NOT is_deleted", userId).
+		ToSql()
+
+	if err != nil {
+		return nil, SqlStatementError
+	}
+
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, pgError(err, SqlReadError)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, SqlScanError
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type IdempotencyKeyRepository struct {
+	pool    *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewIdempotencyKeyRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderType) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{pool: pool, builder: builder}
+}
+
+// Reserve stores the key as in progress and reports whether it was free. A
+// key that has expired, or whose request started before staleBefore and never
+// finished, is taken over.
+func (repo *IdempotencyKeyRepository) Reserve(ctx context.Context, key entities.IdempotencyKey, staleBefore time.Time) (bool, error) {
+	sql, args, err := repo.builder.
+		Insert("idempotency_keys").
+		Columns("user_id", "key", "fingerprint", "created_at", "expires_at").
+		Values(key.UserId, key.Key, key.Fingerprint, key.CreatedAt, key.ExpiresAt).
+		Suffix(`ON CONFLICT (user_id, key) DO UPDATE SET
+			fingerprint = excluded.fingerprint,
+			status = NULL,
+			header = NULL,
+			body = NULL,
+			created_at = excluded.created_at,
+			expires_at = excluded.expires_at
+			WHERE idempotency_keys.expires_at <= excluded.created_at
+			OR (idempotency_keys.status IS NULL AND idempotency_keys.created_at < ?)
+			RETURNING true`, staleBefore).
+		ToSql()
+
+	if err != nil {
+		return false, SqlStatementError
+	}
+
+	var reserved bool
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(&reserved)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, pgError(err, SqlInsertError)
+	}
+
+	return reserved, nil
+}
+
+func (repo *IdempotencyKeyRepository) Read(ctx context.Context, userId int, key string) (entities.IdempotencyKey, error) {
+	var status *int
+	var header map[string]string
+	record := entities.IdempotencyKey{UserId: userId, Key: key}
+
+	sql, args, err := repo.builder.
+		Select("fingerprint", "status", "header", "body", "created_at", "expires_at").
+		From("idempotency_keys").
+		Where(squirrel.Eq{"user_id": userId, "key": key}).
+		ToSql()
+
+	if err != nil {
+		return entities.IdempotencyKey{}, SqlStatementError
+	}
+
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).
+		Scan(&record.Fingerprint, &status, &header, &record.Body, &record.CreatedAt, &record.ExpiresAt)
+	if err != nil {
+		return entities.IdempotencyKey{}, pgError(err, SqlReadError)
+	}
+
+	if status != nil {
+		record.Status = *status
+	}
+	record.Header = header
+
+	return record, nil
+}
+
+// Complete stores the response to the request the key was reserved for.
+func (repo *IdempotencyKeyRepository) Complete(ctx context.Context, key entities.IdempotencyKey) error {
+	header, err := json.Marshal(key.Header)
+	if err != nil {
+		return SqlStatementError
+	}
+
+	sql, args, err := repo.builder.
+		Update("idempotency_keys").
+		Set("status", key.Status).
+		Set("header", string(header)).
+		Set("body", key.Body).
+		Set("expires_at", key.ExpiresAt).
+		Where(squirrel.Eq{"user_id": key.UserId, "key": key.Key, "fingerprint": key.Fingerprint}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlUpdateError)
+	}
+
+	return nil
+}
+
+func (repo *IdempotencyKeyRepository) Delete(ctx context.Context, userId int, key string) error {
+	sql, args, err := repo.builder.
+		Delete("idempotency_keys").
+		Where(squirrel.Eq{"user_id": userId, "key": key}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlDeleteError)
+	}
+
+	return nil
+}
+
+func (repo *IdempotencyKeyRepository) DeleteExpired(ctx context.Context) error {
+	sql, args, err := repo.builder.
+		Delete("idempotency_keys").
+		Where(squirrel.LtOrEq{"expires_at": time.Now()}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlDeleteError)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"backendForKeenEye/internal/entities"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"strings"
+)
+
+// sortColumns maps the fields a list can be sorted by to the expressions it
+// is sorted on. Nullable columns are coalesced so that keyset comparisons
+// never meet a NULL.
+type sortColumns map[string]string
+
+// page orders query by the sort field and the id, skips the records up to the
+// cursor and limits the result.
+func page(query squirrel.SelectBuilder, list entities.ListQuery, columns sortColumns) (squirrel.SelectBuilder, error) {
+	column, ok := columns[list.Sort]
+	if !ok {
+		return query, SqlStatementError
+	}
+
+	direction, comparison := "ASC", ">"
+	if list.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if list.After != nil {
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, comparison), list.After.Value, list.After.Id)
+	}
+	query = query.OrderBy(column+" "+direction, "id "+direction)
+	if list.Limit > 0 {
+		query = query.Limit(uint64(list.Limit))
+	}
+
+	return query, nil
+}
+
+// containsPattern matches text containing s case-insensitively with ILIKE.
+func containsPattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+}
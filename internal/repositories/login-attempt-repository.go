@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type LoginAttemptRepository struct {
+	pool    *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewLoginAttemptRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderType) *LoginAttemptRepository {
+	return &LoginAttemptRepository{pool: pool, builder: builder}
+}
+
+func (repo *LoginAttemptRepository) Read(ctx context.Context, key string) (entities.LoginAttempt, error) {
+	var failures int
+	var lastFailure time.Time
+	var lockedUntil sql.NullTime
+
+	sql, args, err := repo.builder.
+		Select("failures", "last_failure", "locked_until").
+		From("login_attempts").
+		Where(squirrel.Eq{"key": key}).
+		ToSql()
+
+	if err != nil {
+		return entities.LoginAttempt{}, SqlStatementError
+	}
+
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(&failures, &lastFailure, &lockedUntil)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entities.LoginAttempt{Key: key}, nil
+	}
+	if err != nil {
+		return entities.LoginAttempt{}, SqlReadError
+	}
+
+	return entities.LoginAttempt{Key: key, Failures: failures, LastFailure: lastFailure, LockedUntil: lockedUntil.Time}, nil
+}
+
+// RegisterFailure increments the failure counter of the key. Failures older
+// than window are forgotten, so the counter starts over from one.
+func (repo *LoginAttemptRepository) RegisterFailure(ctx context.Context, key string, window time.Duration) (entities.LoginAttempt, error) {
+	var failures int
+	var lastFailure time.Time
+	var lockedUntil sql.NullTime
+
+	now := time.Now()
+	sql, args, err := repo.builder.
+		Insert("login_attempts").
+		Columns("key", "failures", "last_failure").
+		Values(key, 1, now).
+		Suffix(`ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.last_failure < ? THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure = excluded.last_failure
+			RETURNING failures, last_failure, locked_until`, now.Add(-window)).
+		ToSql()
+
+	if err != nil {
+		return entities.LoginAttempt{}, SqlStatementError
+	}
+
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(&failures, &lastFailure, &lockedUntil)
+	if err != nil {
+		return entities.LoginAttempt{}, SqlUpdateError
+	}
+
+	return entities.LoginAttempt{Key: key, Failures: failures, LastFailure: lastFailure, LockedUntil: lockedUntil.Time}, nil
+}
+
+func (repo *LoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	sql, args, err := repo.builder.
+		Update("login_attempts").
+		Set("locked_until", until).
+		Where(squirrel.Eq{"key": key}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return SqlUpdateError
+	}
+
+	return nil
+}
+
+func (repo *LoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	sql, args, err := repo.builder.
+		Delete("login_attempts").
+		Where(squirrel.Eq{"key": key}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return SqlDeleteError
+	}
+
+	return nil
+}
+
+func (repo *LoginAttemptRepository) ReadActive(ctx context.Context, since time.Time) ([]entities.LoginAttempt, error) {
+	var key string
+	var failures int
+	var lastFailure time.Time
+	var lockedUntil sql.NullTime
+
+	sql, args, err := repo.builder.
+		Select("key", "failures", "last_failure", "locked_until").
+		From("login_attempts").
+		Where(squirrel.Or{
+			squirrel.GtOrEq{"last_failure": since},
+			squirrel.Gt{"locked_until": time.Now()},
+		}).
+		OrderBy("last_failure DESC").
+		ToSql()
+
+	if err != nil {
+		return nil, SqlStatementError
+	}
+
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, SqlReadError
+	}
+	defer rows.Close()
+
+	var attempts []entities.LoginAttempt
+	for rows.Next() {
+		err = rows.Scan(&key, &failures, &lastFailure, &lockedUntil)
+		if err != nil {
+			return nil, SqlScanError
+		}
+
+		attempts = append(attempts, entities.LoginAttempt{Key: key, Failures: failures, LastFailure: lastFailure, LockedUntil: lockedUntil.Time})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return attempts, nil
+}
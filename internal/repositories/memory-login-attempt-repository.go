@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryLoginAttemptRepository keeps login attempt counters in process
+// memory. It is enough for a single instance; counters are lost on restart.
+type MemoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]entities.LoginAttempt
+}
+
+func NewMemoryLoginAttemptRepository() *MemoryLoginAttemptRepository {
+	return &MemoryLoginAttemptRepository{attempts: make(map[string]entities.LoginAttempt)}
+}
+
+func (repo *MemoryLoginAttemptRepository) Read(ctx context.Context, key string) (entities.LoginAttempt, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	attempt, ok := repo.attempts[key]
+	if !ok {
+		return entities.LoginAttempt{Key: key}, nil
+	}
+
+	return attempt, nil
+}
+
+func (repo *MemoryLoginAttemptRepository) RegisterFailure(ctx context.Context, key string, window time.Duration) (entities.LoginAttempt, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	now := time.Now()
+	attempt, ok := repo.attempts[key]
+	if !ok || attempt.LastFailure.Before(now.Add(-window)) {
+		attempt = entities.LoginAttempt{Key: key, LockedUntil: attempt.LockedUntil}
+	}
+
+	attempt.Failures++
+	attempt.LastFailure = now
+	repo.attempts[key] = attempt
+
+	return attempt, nil
+}
+
+func (repo *MemoryLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if attempt, ok := repo.attempts[key]; ok {
+		attempt.LockedUntil = until
+		repo.attempts[key] = attempt
+	}
+
+	return nil
+}
+
+func (repo *MemoryLoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.attempts, key)
+	return nil
+}
+
+func (repo *MemoryLoginAttemptRepository) ReadActive(ctx context.Context, since time.Time) ([]entities.LoginAttempt, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	now := time.Now()
+	var attempts []entities.LoginAttempt
+	for key, attempt := range repo.attempts {
+		switch {
+		case !attempt.LastFailure.Before(since), attempt.IsLocked(now):
+			attempts = append(attempts, attempt)
+		case !attempt.IsLocked(now):
+			// Stale entries are dropped lazily so the map doesn't grow
+			// forever under a distributed guessing attack.
+			delete(repo.attempts, key)
+		}
+	}
+
+	sort.Slice(attempts, func(i, j int) bool {
+		return attempts[i].LastFailure.After(attempts[j].LastFailure)
+	})
+
+	return attempts, nil
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PasswordResetTokenRepository struct {
+	pool    *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewPasswordResetTokenRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderType) *PasswordResetTokenRepository {
+	return &PasswordResetTokenRepository{pool: pool, builder: builder}
+}
+
+func (repo *PasswordResetTokenRepository) Create(ctx context.Context, token entities.PasswordResetToken) (int, error) {
+	sql, args, err := repo.builder.
+		Insert("password_reset_tokens").
+		Columns("user_id", "token_hash", "expires_at").
+		Values(token.UserId, token.TokenHash, token.ExpiresAt).
+		Suffix("RETURNING id").
+		ToSql()
+
+	if err != nil {
+		return 0, SqlStatementError
+	}
+
+	var newID int
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(&newID)
+	if err != nil {
+		return 0, pgError(err, SqlInsertError)
+	}
+
+	return newID, nil
+}
+
+// InvalidateByUserId marks every outstanding token of the user as used, so
+// that only the most recently requested one is valid.
+func (repo *PasswordResetTokenRepository) InvalidateByUserId(ctx context.Context, userId int) error {
+	sql, args, err := repo.builder.
+		Update("password_reset_tokens").
+		Set("used_at", squirrel.Expr("now()")).
+		Where(squirrel.Eq{"user_id": userId, "used_at": nil}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlUpdateError)
+	}
+
+	return nil
+}
+
+// Consume marks an unused, unexpired token as used and returns the id of its
+// user. Checking and consuming happen in one statement so a token can't be
+// redeemed twice by concurrent requests.
+func (repo *PasswordResetTokenRepository) Consume(ctx context.Context, tokenHash string) (int, error) {
+	sql, args, err := repo.builder.
+		Update("password_reset_tokens").
+		Set("used_at", squirrel.Expr("now()")).
+		Where(squirrel.Eq{"token_hash": tokenHash, "used_at": nil}).
+		Where("expires_at > now()").
+		Suffix("RETURNING user_id").
+		ToSql()
+
+	if err != nil {
+		return 0, SqlStatementError
+	}
+
+	var userId int
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(&userId)
+	if err != nil {
+		return 0, pgError(err, SqlReadError)
+	}
+
+	return userId, nil
+}
+
+// ReadUserId returns the user of an unused, unexpired token without
+// consuming it.
+func (repo *PasswordResetTokenRepository) ReadUserId(ctx context.Context, tokenHash string) (int, error) {
+	sql, args, err := repo.builder.
+		Select("user_id").
+		From("password_reset_tokens").
+		Where(squirrel.Eq{"token_hash": tokenHash, "used_at": nil}).
+		Where("expires_at > now()").
+		ToSql()
+
+	if err != nil {
+		return 0, SqlStatementError
+	}
+
+	var userId int
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(&userId)
+	if err != nil {
+		return 0, pgError(err, SqlReadError)
+	}
+
+	return userId, nil
+}
@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type RefreshTokenRepository struct {
+	pool    *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewRefreshTokenRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderType) *RefreshTokenRepository {
+	return &RefreshTokenRepository{pool: pool, builder: builder}
+}
+
+func (repo *RefreshTokenRepository) Create(ctx context.Context, token entities.RefreshToken) error {
+	sql, args, err := repo.builder.
+		Insert("refresh_tokens").
+		Columns("id", "user_id", "family_id", "expires_at").
+		Values(token.Id, token.UserId, token.FamilyId, token.ExpiresAt).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlInsertError)
+	}
+
+	return nil
+}
+
+func (repo *RefreshTokenRepository) ReadById(ctx context.Context, id string) (entities.RefreshToken, error) {
+	var userId int
+	var familyId string
+	var expiresAt time.Time
+	var revoked bool
+	var replacedBy sql.NullString
+
+	sql, args, err := repo.builder.
+		Select("user_id", "family_id", "expires_at", "revoked", "replaced_by").
+		From("refresh_tokens").
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return entities.RefreshToken{}, SqlStatementError
+	}
+
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(
+		&userId,
+		&familyId,
+		&expiresAt,
+		&revoked,
+		&replacedBy,
+	)
+	if err != nil {
+		return entities.RefreshToken{}, pgError(err, SqlReadError)
+	}
+
+	return entities.RefreshToken{
+		Id:         id,
+		UserId:     userId,
+		FamilyId:   familyId,
+		ExpiresAt:  expiresAt,
+		Revoked:    revoked,
+		ReplacedBy: validateString(replacedBy),
+	}, nil
+}
+
+// Rotate revokes the old token and stores its replacement atomically. If the
+// old token has already been revoked (e.g. two concurrent refreshes with the
+// same token) nothing is written and SqlConflictError is returned.
+func (repo *RefreshTokenRepository) Rotate(ctx context.Context, oldId string, newToken entities.RefreshToken) (err error) {
+	tx, err := executor(ctx, repo.pool).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	sql, args, err := repo.builder.
+		Update("refresh_tokens").
+		Set("revoked", true).
+		Set("replaced_by", newToken.Id).
+		Where(squirrel.Eq{"id": oldId, "revoked": false}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlUpdateError)
+	}
+	if tag.RowsAffected() == 0 {
+		return SqlConflictError
+	}
+
+	sql, args, err = repo.builder.
+		Insert("refresh_tokens").
+		Columns("id", "user_id", "family_id", "expires_at").
+		Values(newToken.Id, newToken.UserId, newToken.FamilyId, newToken.ExpiresAt).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlInsertError)
+	}
+
+	return nil
+}
+
+func (repo *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyId string) error {
+	sql, args, err := repo.builder.
+		Update("refresh_tokens").
+		Set("revoked", true).
+		Where(squirrel.Eq{"family_id": familyId, "revoked": false}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlUpdateError)
+	}
+
+	return nil
+}
+
+func (repo *RefreshTokenRepository) RevokeByUserId(ctx context.Context, userId int) error {
+	sql, args, err := repo.builder.
+		Update("refresh_tokens").
+		Set("revoked", true).
+		Where(squirrel.Eq{"user_id": userId, "revoked": false}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlUpdateError)
+	}
+
+	return nil
+}
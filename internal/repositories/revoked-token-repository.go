@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type RevokedTokenRepository struct {
+	pool    *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewRevokedTokenRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderType) *RevokedTokenRepository {
+	return &RevokedTokenRepository{pool: pool, builder: builder}
+}
+
+func (repo *RevokedTokenRepository) Create(ctx context.Context, token entities.RevokedToken) error {
+	sql, args, err := repo.builder.
+		Insert("revoked_access_tokens").
+		Columns("id", "user_id", "expires_at").
+		Values(token.Id, token.UserId, token.ExpiresAt).
+		Suffix("ON CONFLICT (id) DO NOTHING").
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlInsertError)
+	}
+
+	return nil
+}
+
+func (repo *RevokedTokenRepository) ReadActive(ctx context.Context) ([]entities.RevokedToken, error) {
+	var id string
+	var userId int
+	var expiresAt time.Time
+	sql, args, err := repo.builder.
+		Select("id", "user_id", "expires_at").
+		From("revoked_access_tokens").
+		Where(squirrel.Gt{"expires_at": time.Now()}).
+		ToSql()
+
+	if err != nil {
+		return nil, SqlStatementError
+	}
+
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, pgError(err, SqlReadError)
+	}
+	defer rows.Close()
+
+	var tokens []entities.RevokedToken
+	for rows.Next() {
+		err = rows.Scan(&id, &userId, &expiresAt)
+		if err != nil {
+			return nil, SqlScanError
+		}
+
+		tokens = append(tokens, entities.RevokedToken{Id: id, UserId: userId, ExpiresAt: expiresAt})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (repo *RevokedTokenRepository) DeleteExpired(ctx context.Context) error {
+	sql, args, err := repo.builder.
+		Delete("revoked_access_tokens").
+		Where(squirrel.LtOrEq{"expires_at": time.Now()}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlDeleteError)
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RoleRepository struct {
+	pool    *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewRoleRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderType) *RoleRepository {
+	return &RoleRepository{pool: pool, builder: builder}
+}
+
+func (repo *RoleRepository) Create(ctx context.Context, role entities.Role) error {
+	sql, args, err := repo.builder.
+		Insert("roles").
+		Columns("name", "description").
+		Values(role.Name, nullableString(role.Description)).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		if pgErrorCode(err) == pgUniqueViolation {
+			return entities.DuplicateRoleError
+		}
+		return SqlInsertError
+	}
+
+	return nil
+}
+
+// ReadAll returns every role together with its grants.
+func (repo *RoleRepository) ReadAll(ctx context.Context) ([]entities.Role, error) {
+	var name string
+	var description, permission, scope sql.NullString
+	sql, args, err := repo.builder.
+		Select("r.name", "r.description", "rp.permission", "rp.scope").
+		From("roles r").
+		LeftJoin("role_permissions rp ON rp.role = r.name").
+		OrderBy("r.name", "rp.permission").
+		ToSql()
+
+	if err != nil {
+		return nil, SqlStatementError
+	}
+
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, pgError(err, SqlReadError)
+	}
+	defer rows.Close()
+
+	var roles []entities.Role
+	for rows.Next() {
+		err = rows.Scan(&name, &description, &permission, &scope)
+		if err != nil {
+			return nil, SqlScanError
+		}
+
+		if len(roles) == 0 || roles[len(roles)-1].Name != name {
+			roles = append(roles, entities.Role{Name: name, Description: validateString(description), Grants: []entities.Grant{}})
+		}
+		if permission.Valid {
+			role := &roles[len(roles)-1]
+			role.Grants = append(role.Grants, entities.Grant{Permission: permission.String, Scope: entities.Scope(scope.String)})
+		}
+	}
+
+	return roles, nil
+}
+
+func (repo *RoleRepository) ReadPermissions(ctx context.Context) ([]entities.Permission, error) {
+	var name string
+	var description sql.NullString
+	sql, args, err := repo.builder.
+		Select("name", "description").
+		From("permissions").
+		OrderBy("name").
+		ToSql()
+
+	if err != nil {
+		return nil, SqlStatementError
+	}
+
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, pgError(err, SqlReadError)
+	}
+	defer rows.Close()
+
+	var permissions []entities.Permission
+	for rows.Next() {
+		err = rows.Scan(&name, &description)
+		if err != nil {
+			return nil, SqlScanError
+		}
+
+		permissions = append(permissions, entities.Permission{Name: name, Description: validateString(description)})
+	}
+
+	return permissions, nil
+}
+
+// ReplaceGrants swaps the grants of the role for the given ones atomically.
+func (repo *RoleRepository) ReplaceGrants(ctx context.Context, role string, grants []entities.Grant) (err error) {
+	tx, err := executor(ctx, repo.pool).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	sql, args, err := repo.builder.
+		Select("1").
+		From("roles").
+		Where(squirrel.Eq{"name": role}).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	var exists int
+	err = tx.QueryRow(ctx, sql, args...).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entities.UnknownRoleError
+		}
+		return SqlReadError
+	}
+
+	sql, args, err = repo.builder.
+		Delete("role_permissions").
+		Where(squirrel.Eq{"role": role}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlDeleteError)
+	}
+
+	if len(grants) == 0 {
+		return nil
+	}
+
+	insert := repo.builder.
+		Insert("role_permissions").
+		Columns("role", "permission", "scope")
+	for _, grant := range grants {
+		insert = insert.Values(role, grant.Permission, string(grant.Scope))
+	}
+
+	sql, args, err = insert.ToSql()
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return entities.UnknownPermissionError
+		}
+		return SqlInsertError
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SearchRepository finds students, teachers and groups by the normalized
+// text of their fields, using the pg_trgm and full text indexes.
+type SearchRepository struct {
+	pool    *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewSearchRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderType) *SearchRepository {
+	return &SearchRepository{pool: pool, builder: builder}
+}
+
+// SearchStudents returns the visible students whose fio, login or phone
+// number match the query, most relevant first.
+func (repo *SearchRepository) SearchStudents(ctx context.Context, query entities.SearchQuery) ([]entities.SearchHit, error) {
+	visible := squirrel.Or{squirrel.Eq{"s.id": query.Visibility.OwnerId}, squirrel.Eq{"s.group_id": query.Visibility.GroupIds}}
+	return repo.searchPeople(ctx, "students", entities.SearchHitStudent, query, visible)
+}
+
+// SearchTeachers returns the visible teachers whose fio, login or phone
+// number match the query, most relevant first.
+func (repo *SearchRepository) SearchTeachers(ctx context.Context, query entities.SearchQuery) ([]entities.SearchHit, error) {
+	visible := squirrel.Eq{"s.id": query.Visibility.OwnerId}
+	return repo.searchPeople(ctx, "teachers", entities.SearchHitTeacher, query, visible)
+}
+
+// SearchGroups returns the visible groups whose name matches the query, most
+// relevant first.
+func (repo *SearchRepository) SearchGroups(ctx context.Context, query entities.SearchQuery) ([]entities.SearchHit, error) {
+	nameMatch, nameRank := textMatch("g.name", query.Text, true)
+
+	builder := repo.builder.
+		Select("g.id", "coalesce(g.name, '')").
+		Column(squirrel.Alias(nameRank, "rank")).
+		From("groups g").
+		Where(squirrel.Eq{"g.is_deleted": false}).
+		Where(nameMatch).
+		OrderBy("rank DESC", "g.id").
+		Limit(uint64(query.Limit))
+	if !query.Visibility.All {
+		builder = builder.Where(squirrel.Eq{"g.id": query.Visibility.GroupIds})
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, SqlStatementError
+	}
+
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, pgError(err, SqlReadError)
+	}
+	defer rows.Close()
+
+	var hits []entities.SearchHit
+	for rows.Next() {
+		hit := entities.SearchHit{Type: entities.SearchHitGroup}
+		err = rows.Scan(&hit.Id, &hit.Title, &hit.Rank)
+		if err != nil {
+			return nil, SqlScanError
+		}
+		hits = append(hits, hit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return hits, nil
+}
+
+// searchPeople searches a profile table, aliased s, joined with the accounts.
+func (repo *SearchRepository) searchPeople(ctx context.Context, table, hitType string, query entities.SearchQuery, visible squirrel.Sqlizer) ([]entities.SearchHit, error) {
+	var fio, phoneNumber sql.NullString
+	fioMatch, fioRank := textMatch("s.fio", query.Text, true)
+	loginMatch, loginRank := textMatch("u.login", query.Text, false)
+	matches := squirrel.Or{fioMatch, loginMatch}
+	ranks := []any{fioRank, loginRank}
+	if query.Digits != "" {
+		phoneMatch := squirrel.Expr("search_digits(s.phone_number) LIKE ?", containsPattern(query.Digits))
+		matches = append(matches, phoneMatch)
+		ranks = append(ranks, squirrel.Expr("CASE WHEN ? THEN 1 ELSE 0 END", phoneMatch))
+	}
+
+	groupColumn := "0"
+	if table == "students" {
+		groupColumn = "coalesce(s.group_id, 0)"
+	}
+
+	builder := repo.builder.
+		Select("s.id", "s.fio", "s.phone_number", groupColumn, "u.login").
+		Column(squirrel.Alias(squirrel.Expr("greatest("+squirrel.Placeholders(len(ranks))+")", ranks...), "rank")).
+		From(table+" s").
+		Join("users u ON u.id = s.id").
+		Where(squirrel.Eq{"s.is_deleted": false}).
+		Where(matches).
+		OrderBy("rank DESC", "s.id").
+		Limit(uint64(query.Limit))
+	if !query.Visibility.All {
+		builder = builder.Where(visible)
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, SqlStatementError
+	}
+
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, pgError(err, SqlReadError)
+	}
+	defer rows.Close()
+
+	var hits []entities.SearchHit
+	for rows.Next() {
+		hit := entities.SearchHit{Type: hitType}
+		err = rows.Scan(&hit.Id, &fio, &phoneNumber, &hit.GroupId, &hit.Login, &hit.Rank)
+		if err != nil {
+			return nil, SqlScanError
+		}
+
+		hit.Title = validateString(fio)
+		hit.PhoneNumber = validateString(phoneNumber)
+		hits = append(hits, hit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return hits, nil
+}
+
+// textMatch matches the normalized column against the normalized text: as a
+// substring or as a similar word (pg_trgm) and, with fullText, as a set of
+// whole words. The rank is the word similarity, raised by the full text rank.
+func textMatch(column, text string, fullText bool) (match squirrel.Sqlizer, rank squirrel.Sqlizer) {
+	normalized := "search_normalize(" + column + ")"
+	conditions := squirrel.Or{
+		squirrel.Expr(normalized+" LIKE ?", containsPattern(text)),
+		squirrel.Expr("? <% "+normalized, text),
+	}
+	rank = squirrel.Expr("word_similarity(?, "+normalized+")", text)
+
+	if fullText {
+		document := "to_tsvector('simple', " + normalized + ")"
+		conditions = append(conditions, squirrel.Expr(document+" @@ plainto_tsquery('simple', ?)", text))
+		rank = squirrel.Expr("? + ts_rank("+document+", plainto_tsquery('simple', ?))", rank, text)
+	}
+
+	return conditions, rank
+}
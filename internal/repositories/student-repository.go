@@ -18,36 +18,65 @@ func NewStudentRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderT
 	return &StudentRepository{pool: pool, builder: builder}
 }
 
-func (repo *StudentRepository) Read(ctx context.Context) ([]entities.Student, error) {
-	var id int
+var studentSortColumns = sortColumns{
+	"id":       "id",
+	"fio":      "coalesce(fio, '')",
+	"group_id": "coalesce(group_id, 0)",
+}
+
+// Read returns a page of the students that aren't deleted.
+func (repo *StudentRepository) Read(ctx context.Context, list entities.ListQuery) ([]entities.Student, error) {
+	var students []entities.Student
+	err := repo.Each(ctx, list, func(student entities.Student) error {
+		students = append(students, student)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return students, nil
+}
+
+// Each calls fn with the students that aren't deleted in the order of list as
+// they are read, without holding them all in memory. It stops at the first
+// error fn returns.
+func (repo *StudentRepository) Each(ctx context.Context, list entities.ListQuery, fn func(entities.Student) error) error {
+	var id, version int
 	var fio, phoneNumber sql.NullString
 	var groupId sql.NullInt32
-	sql, args, err := repo.builder.
-		Select("id", "fio", "phone_number", "group_id").
+	query := repo.builder.
+		Select("id", "fio", "phone_number", "group_id", "version").
 		From("students").
 		Where(squirrel.Eq{"is_deleted": false}).
-		ToSql()
+		Where(studentFilter(list.Filter))
 
+	query, err := page(query, list, studentSortColumns)
 	if err != nil {
-		return nil, SqlStatementError
+		return err
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return SqlStatementError
 	}
 
-	rows, err := repo.pool.Query(ctx, sql, args...)
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
 	if err != nil {
-		return nil, SqlReadError
+		return pgError(err, SqlReadError)
 	}
 	defer rows.Close()
 
-	var students []entities.Student
 	for rows.Next() {
 		err = rows.Scan(
 			&id,
 			&fio,
 			&phoneNumber,
 			&groupId,
+			&version,
 		)
 		if err != nil {
-			return nil, SqlScanError
+			return SqlScanError
 		}
 
 		var student = entities.Student{
@@ -55,39 +84,95 @@ func (repo *StudentRepository) Read(ctx context.Context) ([]entities.Student, er
 			Fio:         validateString(fio),
 			PhoneNumber: validateString(phoneNumber),
 			GroupId:     validateInt(groupId),
+			Version:     version,
 		}
 
-		students = append(students, student)
+		if err = fn(student); err != nil {
+			return err
+		}
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows error: %w", err)
+		return fmt.Errorf("rows error: %w", err)
 	}
 
-	return students, nil
+	return nil
+}
+
+// Count returns the number of students that aren't deleted and match filter.
+func (repo *StudentRepository) Count(ctx context.Context, filter entities.ListFilter) (int, error) {
+	sql, args, err := repo.builder.
+		Select("count(*)").
+		From("students").
+		Where(squirrel.Eq{"is_deleted": false}).
+		Where(studentFilter(filter)).
+		ToSql()
+
+	if err != nil {
+		return 0, SqlStatementError
+	}
+
+	var count int
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(&count)
+	if err != nil {
+		return 0, pgError(err, SqlReadError)
+	}
+
+	return count, nil
+}
+
+func studentFilter(filter entities.ListFilter) squirrel.And {
+	conditions := squirrel.And{}
+	if filter.Fio != "" {
+		conditions = append(conditions, squirrel.ILike{"fio": containsPattern(filter.Fio)})
+	}
+	if filter.GroupId != 0 {
+		conditions = append(conditions, squirrel.Eq{"group_id": filter.GroupId})
+	}
+	if filter.TeacherId != 0 {
+		conditions = append(conditions, squirrel.Expr("group_id IN (SELECT id FROM groups WHERE teacher_id = ? AND NOT is_deleted)", filter.TeacherId))
+	}
+	if filter.NoGroup {
+		conditions = append(conditions, squirrel.Eq{"group_id": nil})
+	}
+	return conditions
 }
 
+// ReadById returns the student unless it is deleted.
 func (repo *StudentRepository) ReadById(ctx context.Context, id int) (entities.Student, error) {
+	return repo.readById(ctx, id, false)
+}
+
+func (repo *StudentRepository) ReadByIdIncludingDeleted(ctx context.Context, id int) (entities.Student, error) {
+	return repo.readById(ctx, id, true)
+}
+
+func (repo *StudentRepository) readById(ctx context.Context, id int, includeDeleted bool) (entities.Student, error) {
 	var fio, phoneNumber sql.NullString
 	var groupId sql.NullInt32
+	var version int
 
-	sql, args, err := repo.builder.
-		Select("fio", "phone_number", "group_id").
+	query := repo.builder.
+		Select("fio", "phone_number", "group_id", "version").
 		From("students").
-		Where(squirrel.Eq{"id": id}).
-		ToSql()
+		Where(squirrel.Eq{"id": id})
+	if !includeDeleted {
+		query = query.Where(squirrel.Eq{"is_deleted": false})
+	}
 
+	sql, args, err := query.ToSql()
 	if err != nil {
 		return entities.Student{}, SqlStatementError
 	}
 
-	err = repo.pool.QueryRow(ctx, sql, args...).Scan(
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(
 		&fio,
 		&phoneNumber,
 		&groupId,
+		&version,
 	)
 	if err != nil {
-		return entities.Student{}, SqlReadError
+		return entities.Student{}, pgError(err, SqlReadError)
 	}
 
 	return entities.Student{
@@ -95,33 +180,34 @@ func (repo *StudentRepository) ReadById(ctx context.Context, id int) (entities.S
 		Fio:         validateString(fio),
 		PhoneNumber: validateString(phoneNumber),
 		GroupId:     validateInt(groupId),
+		Version:     version,
 	}, nil
 }
 
 func (repo *StudentRepository) ReadByGroupId(ctx context.Context, groupId int) ([]entities.Student, error) {
-	var id int
+	var id, version int
 	var fio, phoneNumber sql.NullString
 
 	sql, args, err := repo.builder.
-		Select("id, fio, phone_number").
+		Select("id, fio, phone_number, version").
 		From("students").
-		Where(squirrel.Eq{"group_id": groupId}).
+		Where(squirrel.Eq{"group_id": groupId, "is_deleted": false}).
 		ToSql()
 
 	if err != nil {
 		return nil, SqlStatementError
 	}
 
-	rows, err := repo.pool.Query(ctx, sql, args...)
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
 	if err != nil {
-		return nil, SqlReadError
+		return nil, pgError(err, SqlReadError)
 	}
 	defer rows.Close()
 
 	var students []entities.Student
 	for rows.Next() {
 
-		err = rows.Scan(&id, &fio, &phoneNumber)
+		err = rows.Scan(&id, &fio, &phoneNumber, &version)
 		if err != nil {
 			return nil, SqlScanError
 		}
@@ -131,6 +217,7 @@ func (repo *StudentRepository) ReadByGroupId(ctx context.Context, groupId int) (
 			Fio:         validateString(fio),
 			PhoneNumber: validateString(phoneNumber),
 			GroupId:     groupId,
+			Version:     version,
 		})
 	}
 
@@ -141,29 +228,34 @@ func (repo *StudentRepository) ReadByGroupId(ctx context.Context, groupId int) (
 	return students, nil
 }
 
-func (repo *StudentRepository) Update(ctx context.Context, id int, updates map[string]any) (entities.Student, error) {
+// Update changes the student if it is still at version.
+func (repo *StudentRepository) Update(ctx context.Context, id int, version int, updates map[string]any) (entities.Student, error) {
 	var fio, phoneNumber sql.NullString
 	var groupId sql.NullInt32
+	var newVersion int
 
-	sql, args, err := repo.builder.
+	query := repo.builder.
 		Update("students").
 		Where(squirrel.Eq{"id": id}).
-		SetMap(updates).
-		Suffix("RETURNING fio, phone_number, group_id").
+		SetMap(updates)
+
+	sql, args, err := bumpVersion(query, version).
+		Suffix("RETURNING fio, phone_number, group_id, version").
 		ToSql()
 
 	if err != nil {
 		return entities.Student{}, SqlStatementError
 	}
 
-	err = repo.pool.QueryRow(ctx, sql, args...).Scan(
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(
 		&fio,
 		&phoneNumber,
 		&groupId,
+		&newVersion,
 	)
 
 	if err != nil {
-		return entities.Student{}, SqlUpdateError
+		return entities.Student{}, pgError(err, SqlUpdateError)
 	}
 
 	return entities.Student{
@@ -171,23 +263,29 @@ func (repo *StudentRepository) Update(ctx context.Context, id int, updates map[s
 		Fio:         validateString(fio),
 		PhoneNumber: validateString(phoneNumber),
 		GroupId:     validateInt(groupId),
+		Version:     newVersion,
 	}, nil
 }
 
-func (repo *StudentRepository) SoftDelete(ctx context.Context, id int) error {
-	sql, args, err := repo.builder.
+// SoftDelete marks the student deleted if it is still at version.
+func (repo *StudentRepository) SoftDelete(ctx context.Context, id int, version int) error {
+	query := repo.builder.
 		Update("students").
 		Where(squirrel.Eq{"id": id}).
 		Set("is_deleted", true).
-		ToSql()
+		Set("deleted_at", squirrel.Expr("now()"))
 
+	sql, args, err := bumpVersion(query, version).ToSql()
 	if err != nil {
 		return SqlStatementError
 	}
 
-	_, err = repo.pool.Exec(ctx, sql, args...)
+	tag, err := executor(ctx, repo.pool).Exec(ctx, sql, args...)
 	if err != nil {
-		return SqlDeleteError
+		return pgError(err, SqlDeleteError)
+	}
+	if tag.RowsAffected() == 0 {
+		return entities.RecordNotFoundError
 	}
 
 	return nil
@@ -18,119 +18,210 @@ func NewTeacherRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderT
 	return &TeacherRepository{pool: pool, builder: builder}
 }
 
-func (repo *TeacherRepository) Read(ctx context.Context) ([]entities.Teacher, error) {
+var teacherSortColumns = sortColumns{
+	"id":  "id",
+	"fio": "coalesce(fio, '')",
+}
+
+// Read returns a page of the teachers that aren't deleted.
+func (repo *TeacherRepository) Read(ctx context.Context, list entities.ListQuery) ([]entities.Teacher, error) {
+	var teachers []entities.Teacher
+	err := repo.Each(ctx, list, func(teacher entities.Teacher) error {
+		teachers = append(teachers, teacher)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return teachers, nil
+}
+
+// Each calls fn with the teachers that aren't deleted in the order of list as
+// they are read, without holding them all in memory. It stops at the first
+// error fn returns.
+func (repo *TeacherRepository) Each(ctx context.Context, list entities.ListQuery, fn func(entities.Teacher) error) error {
 	var id sql.NullInt32
+	var version int
 	var fio, phoneNumber sql.NullString
-	sql, args, err := repo.builder.
-		Select("id, fio, phone_number").
+	query := repo.builder.
+		Select("id", "fio", "phone_number", "version").
 		From("teachers").
 		Where(squirrel.Eq{"is_deleted": false}).
-		ToSql()
+		Where(teacherFilter(list.Filter))
 
+	query, err := page(query, list, teacherSortColumns)
 	if err != nil {
-		return nil, SqlStatementError
+		return err
 	}
 
-	rows, err := repo.pool.Query(ctx, sql, args...)
+	sql, args, err := query.ToSql()
 	if err != nil {
-		return nil, SqlReadError
+		return SqlStatementError
+	}
+
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlReadError)
 	}
 	defer rows.Close()
 
-	var teachers []entities.Teacher
 	for rows.Next() {
 		err = rows.Scan(
 			&id,
 			&fio,
 			&phoneNumber,
+			&version,
 		)
 		if err != nil {
-			return nil, SqlScanError
+			return SqlScanError
 		}
 
 		teacher := entities.Teacher{
 			Id:          validateInt(id),
 			Fio:         validateString(fio),
 			PhoneNumber: validateString(phoneNumber),
+			Version:     version,
+		}
+
+		if err = fn(teacher); err != nil {
+			return err
 		}
-		teachers = append(teachers, teacher)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows error: %w", err)
+		return fmt.Errorf("rows error: %w", err)
 	}
 
-	return teachers, nil
+	return nil
 }
 
+// Count returns the number of teachers that aren't deleted and match filter.
+func (repo *TeacherRepository) Count(ctx context.Context, filter entities.ListFilter) (int, error) {
+	sql, args, err := repo.builder.
+		Select("count(*)").
+		From("teachers").
+		Where(squirrel.Eq{"is_deleted": false}).
+		Where(teacherFilter(filter)).
+		ToSql()
+
+	if err != nil {
+		return 0, SqlStatementError
+	}
+
+	var count int
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(&count)
+	if err != nil {
+		return 0, pgError(err, SqlReadError)
+	}
+
+	return count, nil
+}
+
+func teacherFilter(filter entities.ListFilter) squirrel.And {
+	conditions := squirrel.And{}
+	if filter.Fio != "" {
+		conditions = append(conditions, squirrel.ILike{"fio": containsPattern(filter.Fio)})
+	}
+	if filter.NoGroup {
+		conditions = append(conditions, squirrel.Expr("NOT EXISTS (SELECT 1 FROM groups WHERE groups.teacher_id = teachers.id AND NOT groups.is_deleted)"))
+	}
+	return conditions
+}
+
+// ReadById returns the teacher unless it is deleted.
 func (repo *TeacherRepository) ReadById(ctx context.Context, id int) (entities.Teacher, error) {
+	return repo.readById(ctx, id, false)
+}
+
+func (repo *TeacherRepository) ReadByIdIncludingDeleted(ctx context.Context, id int) (entities.Teacher, error) {
+	return repo.readById(ctx, id, true)
+}
+
+func (repo *TeacherRepository) readById(ctx context.Context, id int, includeDeleted bool) (entities.Teacher, error) {
 	var fio, phoneNumber sql.NullString
+	var version int
 
-	sql, args, err := repo.builder.
-		Select("fio", "phone_number").
+	query := repo.builder.
+		Select("fio", "phone_number", "version").
 		From("teachers").
-		Where(squirrel.Eq{"id": id}).
-		ToSql()
+		Where(squirrel.Eq{"id": id})
+	if !includeDeleted {
+		query = query.Where(squirrel.Eq{"is_deleted": false})
+	}
 
+	sql, args, err := query.ToSql()
 	if err != nil {
 		return entities.Teacher{}, SqlStatementError
 	}
 
-	err = repo.pool.QueryRow(ctx, sql, args...).Scan(
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(
 		&fio,
 		&phoneNumber,
+		&version,
 	)
 	if err != nil {
-		return entities.Teacher{}, SqlReadError
+		return entities.Teacher{}, pgError(err, SqlReadError)
 	}
 
-	return entities.Teacher{Id: id, Fio: validateString(fio), PhoneNumber: validateString(phoneNumber)}, nil
+	return entities.Teacher{Id: id, Fio: validateString(fio), PhoneNumber: validateString(phoneNumber), Version: version}, nil
 }
 
-func (repo *TeacherRepository) Update(ctx context.Context, id int, updates map[string]any) (entities.Teacher, error) {
+// Update changes the teacher if it is still at version.
+func (repo *TeacherRepository) Update(ctx context.Context, id int, version int, updates map[string]any) (entities.Teacher, error) {
 	var fio, phoneNumber sql.NullString
-	sql, args, err := repo.builder.
+	var newVersion int
+
+	query := repo.builder.
 		Update("teachers").
 		Where(squirrel.Eq{"id": id}).
-		SetMap(updates).
-		Suffix("RETURNING fio, phone_number").
+		SetMap(updates)
+
+	sql, args, err := bumpVersion(query, version).
+		Suffix("RETURNING fio, phone_number, version").
 		ToSql()
 
 	if err != nil {
 		return entities.Teacher{}, SqlStatementError
 	}
 
-	err = repo.pool.QueryRow(ctx, sql, args...).Scan(
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(
 		&fio,
 		&phoneNumber,
+		&newVersion,
 	)
 
 	if err != nil {
-		fmt.Println(err)
-		return entities.Teacher{}, SqlUpdateError
+		return entities.Teacher{}, pgError(err, SqlUpdateError)
 	}
 
 	return entities.Teacher{
 		Id:          id,
 		Fio:         validateString(fio),
 		PhoneNumber: validateString(phoneNumber),
+		Version:     newVersion,
 	}, nil
 }
 
-func (repo *TeacherRepository) SoftDelete(ctx context.Context, id int) error {
-	sql, args, err := repo.builder.
+// SoftDelete marks the teacher deleted if it is still at version.
+func (repo *TeacherRepository) SoftDelete(ctx context.Context, id int, version int) error {
+	query := repo.builder.
 		Update("teachers").
 		Where(squirrel.Eq{"id": id}).
 		Set("is_deleted", true).
-		ToSql()
+		Set("deleted_at", squirrel.Expr("now()"))
 
+	sql, args, err := bumpVersion(query, version).ToSql()
 	if err != nil {
 		return SqlStatementError
 	}
 
-	_, err = repo.pool.Exec(ctx, sql, args...)
+	tag, err := executor(ctx, repo.pool).Exec(ctx, sql, args...)
 	if err != nil {
-		return SqlDeleteError
+		return pgError(err, SqlDeleteError)
+	}
+	if tag.RowsAffected() == 0 {
+		return entities.RecordNotFoundError
 	}
 
 	return nil
@@ -1,6 +1,18 @@
 package repositories
 
-import "database/sql"
+import (
+	"backendForKeenEye/internal/entities"
+	"database/sql"
+	"errors"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
 
 func validateString(ns sql.NullString) string {
 	if ns.Valid {
@@ -15,3 +27,55 @@ func validateInt(ni sql.NullInt32) int {
 	}
 	return 0
 }
+
+func nullableString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func pgErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// pgError maps the failures every statement can run into to domain errors: a
+// missing row, a unique violation and a foreign key violation. Any other error
+// is replaced by fallback.
+func pgError(err error, fallback error) error {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return entities.RecordNotFoundError
+	case pgErrorCode(err) == pgUniqueViolation:
+		return entities.DuplicateRecordError
+	case pgErrorCode(err) == pgForeignKeyViolation:
+		return entities.UnknownReferenceError
+	}
+	return fallback
+}
+
+func nullableInt(i int) sql.NullInt32 {
+	return sql.NullInt32{Int32: int32(i), Valid: i != 0}
+}
+
+// nullableJSON stores empty documents as NULL.
+func nullableJSON(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
+
+// touch moves the updated record to its next version.
+func touch(query squirrel.UpdateBuilder) squirrel.UpdateBuilder {
+	return query.
+		Set("version", squirrel.Expr("version + 1")).
+		Set("updated_at", squirrel.Expr("now()"))
+}
+
+// bumpVersion makes an update apply only if the record is still at version,
+// so a concurrent change leaves no row to update.
+func bumpVersion(query squirrel.UpdateBuilder, version int) squirrel.UpdateBuilder {
+	return touch(query.Where(squirrel.Eq{"version": version}))
+}
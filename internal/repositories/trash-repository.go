@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+// trashTables maps the soft-deletable entity types to their table and the
+// column used as a display name.
+var trashTables = map[string]struct{ table, name string }{
+	entities.AuditEntityStudent: {"students", "fio"},
+	entities.AuditEntityTeacher: {"teachers", "fio"},
+	entities.AuditEntityAdmin:   {"admins", "fio"},
+	entities.AuditEntityGroup:   {"groups", "name"},
+}
+
+// TrashRepository works with soft-deleted records of every entity type.
+type TrashRepository struct {
+	pool    *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewTrashRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderType) *TrashRepository {
+	return &TrashRepository{pool: pool, builder: builder}
+}
+
+// ReadDeleted returns the deleted records of the entity type that were deleted
+// before the given time; a zero time means all of them.
+func (repo *TrashRepository) ReadDeleted(ctx context.Context, entityType string, before time.Time) ([]entities.DeletedRecord, error) {
+	var id int
+	var name sql.NullString
+	var deletedAt sql.NullTime
+
+	t, ok := trashTables[entityType]
+	if !ok {
+		return nil, entities.RecordNotFoundError
+	}
+
+	query := repo.builder.
+		Select("id", t.name, "deleted_at").
+		From(t.table).
+		Where(squirrel.Eq{"is_deleted": true}).
+		OrderBy("deleted_at DESC NULLS LAST", "id")
+	if !before.IsZero() {
+		query = query.Where(squirrel.Lt{"deleted_at": before})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, SqlStatementError
+	}
+
+	rows, err := executor(ctx, repo.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, pgError(err, SqlReadError)
+	}
+	defer rows.Close()
+
+	records := []entities.DeletedRecord{}
+	for rows.Next() {
+		err = rows.Scan(&id, &name, &deletedAt)
+		if err != nil {
+			return nil, SqlScanError
+		}
+
+		records = append(records, entities.DeletedRecord{EntityType: entityType, Id: id, Name: validateString(name), DeletedAt: deletedAt.Time})
+	}
+
+	return records, nil
+}
+
+func (repo *TrashRepository) ReadDeletedById(ctx context.Context, entityType string, id int) (entities.DeletedRecord, error) {
+	var name sql.NullString
+	var deletedAt sql.NullTime
+
+	t, ok := trashTables[entityType]
+	if !ok {
+		return entities.DeletedRecord{}, entities.RecordNotFoundError
+	}
+
+	sql, args, err := repo.builder.
+		Select(t.name, "deleted_at").
+		From(t.table).
+		Where(squirrel.Eq{"id": id, "is_deleted": true}).
+		ToSql()
+
+	if err != nil {
+		return entities.DeletedRecord{}, SqlStatementError
+	}
+
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(&name, &deletedAt)
+	if err != nil {
+		return entities.DeletedRecord{}, pgError(err, SqlReadError)
+	}
+
+	return entities.DeletedRecord{EntityType: entityType, Id: id, Name: validateString(name), DeletedAt: deletedAt.Time}, nil
+}
+
+func (repo *TrashRepository) Restore(ctx context.Context, entityType string, id int) error {
+	t, ok := trashTables[entityType]
+	if !ok {
+		return entities.RecordNotFoundError
+	}
+
+	query := repo.builder.
+		Update(t.table).
+		Set("is_deleted", false).
+		Set("deleted_at", nil).
+		Where(squirrel.Eq{"id": id, "is_deleted": true})
+
+	sql, args, err := touch(query).ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	tag, err := executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlUpdateError)
+	}
+	if tag.RowsAffected() == 0 {
+		return entities.RecordNotFoundError
+	}
+
+	return nil
+}
+
+// Purge permanently removes a soft-deleted record. People are removed through
+// their users row, which cascades to the role table, tokens and reset
+// requests. References from groups and students are cleared first.
+func (repo *TrashRepository) Purge(ctx context.Context, entityType string, id int) (err error) {
+	t, ok := trashTables[entityType]
+	if !ok {
+		return entities.RecordNotFoundError
+	}
+
+	tx, err := executor(ctx, repo.pool).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	sql, args, err := repo.builder.
+		Select("1").
+		From(t.table).
+		Where(squirrel.Eq{"id": id, "is_deleted": true}).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlReadError)
+	}
+	found := rows.Next()
+	rows.Close()
+	if !found {
+		return entities.RecordNotFoundError
+	}
+
+	var detach squirrel.UpdateBuilder
+	switch entityType {
+	case entities.AuditEntityTeacher:
+		detach = repo.builder.Update("groups").Set("teacher_id", nil).Where(squirrel.Eq{"teacher_id": id})
+	case entities.AuditEntityGroup:
+		detach = repo.builder.Update("students").Set("group_id", nil).Where(squirrel.Eq{"group_id": id})
+	}
+	if entityType == entities.AuditEntityTeacher || entityType == entities.AuditEntityGroup {
+		sql, args, err = touch(detach).ToSql()
+		if err != nil {
+			return SqlStatementError
+		}
+
+		_, err = tx.Exec(ctx, sql, args...)
+		if err != nil {
+			return pgError(err, SqlUpdateError)
+		}
+	}
+
+	table := "users"
+	if entityType == entities.AuditEntityGroup {
+		table = t.table
+	}
+
+	sql, args, err = repo.builder.
+		Delete(table).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlDeleteError)
+	}
+
+	return nil
+}
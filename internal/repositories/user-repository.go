@@ -3,8 +3,8 @@ package repositories
 import (
 	"backendForKeenEye/internal/entities"
 	"context"
+	"database/sql"
 	"github.com/Masterminds/squirrel"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -13,12 +13,22 @@ type UserRepository struct {
 	builder squirrel.StatementBuilderType
 }
 
+// userNotDeleted filters out users whose student, teacher or admin record is
+// soft-deleted. Such users can neither log in nor use issued tokens.
+var userNotDeleted = squirrel.Expr(`NOT EXISTS (SELECT 1 FROM students WHERE students.id = users.id AND students.is_deleted)
+	AND NOT EXISTS (SELECT 1 FROM teachers WHERE teachers.id = users.id AND teachers.is_deleted)
+	AND NOT EXISTS (SELECT 1 FROM admins WHERE admins.id = users.id AND admins.is_deleted)`)
+
 func NewUserRepository(pool *pgxpool.Pool, builder squirrel.StatementBuilderType) *UserRepository {
 	return &UserRepository{pool: pool, builder: builder}
 }
 
-func (repo *UserRepository) Create(ctx context.Context, user entities.User) (int, error) {
-	tx, err := repo.pool.BeginTx(ctx, pgx.TxOptions{})
+// Create inserts the user and its role row in one transaction. A login that
+// is already taken (case-insensitively) yields entities.DuplicateLoginError,
+// an unknown role entities.InvalidRoleError and a student pointing at a
+// missing group entities.UnknownGroupError.
+func (repo *UserRepository) Create(ctx context.Context, user entities.User, profile entities.Profile) (id int, err error) {
+	tx, err := executor(ctx, repo.pool).Begin(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -32,8 +42,8 @@ func (repo *UserRepository) Create(ctx context.Context, user entities.User) (int
 
 	sql, args, err := repo.builder.
 		Insert("users").
-		Columns("login", "password", "salt", "role").
-		Values(user.Login, user.Password, user.Salt, user.Role).
+		Columns("login", "password", "salt", "role", "email", "must_change_password").
+		Values(user.Login, user.Password, user.Salt, user.Role, nullableString(user.Email), user.MustChangePassword).
 		Suffix("RETURNING id").
 		ToSql()
 
@@ -44,6 +54,12 @@ func (repo *UserRepository) Create(ctx context.Context, user entities.User) (int
 	var newID int
 	err = tx.QueryRow(ctx, sql, args...).Scan(&newID)
 	if err != nil {
+		switch pgErrorCode(err) {
+		case pgUniqueViolation:
+			return 0, entities.DuplicateLoginError
+		case pgForeignKeyViolation:
+			return 0, entities.InvalidRoleError
+		}
 		return 0, SqlInsertError
 	}
 
@@ -55,12 +71,23 @@ func (repo *UserRepository) Create(ctx context.Context, user entities.User) (int
 		table = "teachers"
 	case "admin":
 		table = "admins"
+	default:
+		// roles without a profile table only get the users row
+		return newID, nil
+	}
+
+	values := map[string]any{
+		"id":           newID,
+		"fio":          nullableString(profile.Fio),
+		"phone_number": nullableString(profile.PhoneNumber),
+	}
+	if user.Role == "student" && profile.GroupId != 0 {
+		values["group_id"] = profile.GroupId
 	}
 
 	sql, args, err = repo.builder.
 		Insert(table).
-		Columns("id").
-		Values(newID).
+		SetMap(values).
 		ToSql()
 
 	if err != nil {
@@ -69,59 +96,151 @@ func (repo *UserRepository) Create(ctx context.Context, user entities.User) (int
 
 	_, err = tx.Exec(ctx, sql, args...)
 	if err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return 0, entities.UnknownGroupError
+		}
 		return 0, SqlInsertError
 	}
 
 	return newID, nil
 }
 
+// ReadByLogin looks the user up case-insensitively, matching the unique
+// lower(login) index, and returns the login as it is stored.
 func (repo *UserRepository) ReadByLogin(ctx context.Context, login string) (entities.User, error) {
-	var id int
+	var id, tokenVersion, version int
 	var password, salt, role string
+	var mustChangePassword bool
+	var email sql.NullString
 	sql, args, err := repo.builder.
-		Select("id", "password", "salt", "role").
+		Select("id", "login", "password", "salt", "role", "email", "token_version", "must_change_password", "version").
 		From("users").
-		Where(squirrel.Eq{"login": login}).
+		Where(squirrel.Expr("lower(login) = lower(?)", login)).
+		Where(userNotDeleted).
 		ToSql()
 
 	if err != nil {
 		return entities.User{}, SqlStatementError
 	}
 
-	err = repo.pool.QueryRow(ctx, sql, args...).Scan(
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(
 		&id,
+		&login,
 		&password,
 		&salt,
 		&role,
+		&email,
+		&tokenVersion,
+		&mustChangePassword,
+		&version,
 	)
 	if err != nil {
-		return entities.User{}, SqlReadError
+		return entities.User{}, pgError(err, SqlReadError)
 	}
 
-	return entities.User{Id: id, Login: login, Password: password, Salt: salt, Role: role}, nil
+	return entities.User{Id: id, Login: login, Password: password, Salt: salt, Role: role, Email: validateString(email), TokenVersion: tokenVersion, MustChangePassword: mustChangePassword, Version: version}, nil
 }
 
 func (repo *UserRepository) ReadById(ctx context.Context, id int) (entities.User, error) {
 	var login, password, salt, role string
+	var tokenVersion, version int
+	var mustChangePassword bool
+	var email sql.NullString
 	sql, args, err := repo.builder.
-		Select("login", "password", "salt", "role").
+		Select("login", "password", "salt", "role", "email", "token_version", "must_change_password", "version").
 		From("users").
 		Where(squirrel.Eq{"id": id}).
+		Where(userNotDeleted).
 		ToSql()
 
 	if err != nil {
 		return entities.User{}, SqlStatementError
 	}
 
-	err = repo.pool.QueryRow(ctx, sql, args...).Scan(
+	err = executor(ctx, repo.pool).QueryRow(ctx, sql, args...).Scan(
 		&login,
 		&password,
 		&salt,
 		&role,
+		&email,
+		&tokenVersion,
+		&mustChangePassword,
+		&version,
 	)
 	if err != nil {
-		return entities.User{}, SqlReadError
+		return entities.User{}, pgError(err, SqlReadError)
+	}
+
+	return entities.User{Id: id, Login: login, Password: password, Salt: salt, Role: role, Email: validateString(email), TokenVersion: tokenVersion, MustChangePassword: mustChangePassword, Version: version}, nil
+}
+
+func (repo *UserRepository) IncrementTokenVersion(ctx context.Context, id int) error {
+	sql, args, err := repo.builder.
+		Update("users").
+		Set("token_version", squirrel.Expr("token_version + 1")).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	tag, err := executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlUpdateError)
+	}
+	if tag.RowsAffected() == 0 {
+		return entities.RecordNotFoundError
+	}
+
+	return nil
+}
+
+func (repo *UserRepository) UpdatePassword(ctx context.Context, id int, password, salt string, mustChangePassword bool) error {
+	query := repo.builder.
+		Update("users").
+		Set("password", password).
+		Set("salt", salt).
+		Set("must_change_password", mustChangePassword).
+		Where(squirrel.Eq{"id": id})
+
+	sql, args, err := touch(query).ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	tag, err := executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlUpdateError)
+	}
+	if tag.RowsAffected() == 0 {
+		return entities.RecordNotFoundError
+	}
+
+	return nil
+}
+
+// Update changes the user if it is still at version.
+func (repo *UserRepository) Update(ctx context.Context, id int, version int, updates map[string]any) error {
+	query := repo.builder.
+		Update("users").
+		Where(squirrel.Eq{"id": id}).
+		SetMap(updates)
+
+	sql, args, err := bumpVersion(query, version).ToSql()
+
+	if err != nil {
+		return SqlStatementError
+	}
+
+	tag, err := executor(ctx, repo.pool).Exec(ctx, sql, args...)
+	if err != nil {
+		return pgError(err, SqlUpdateError)
+	}
+	if tag.RowsAffected() == 0 {
+		return entities.RecordNotFoundError
 	}
 
-	return entities.User{Id: id, Login: login, Password: password, Salt: salt, Role: role}, nil
+	return nil
 }
@@ -3,53 +3,162 @@ package router
 import (
 	_ "backendForKeenEye/docs"
 	"backendForKeenEye/internal/container"
+	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/internal/middlewares"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"log"
 	"time"
 )
 
 func NewRouter(c *container.Container) *gin.Engine {
 	router := gin.Default()
+	// usecases read the request metadata (and the transaction) from the
+	// context they are given, which is the gin.Context
+	router.ContextWithFallback = true
+	// the client address feeds the login throttling and the audit log, so
+	// forwarding headers count only when a known proxy sets them
+	if err := router.SetTrustedProxies(c.Cfg.TrustedProxies); err != nil {
+		log.Fatalf("failed to set trusted proxies: %v", err)
+	}
 
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Request-Id", "If-Match", "If-None-Match", "Idempotency-Key"},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-Id", "ETag", "Location", "Deprecation", "Sunset", "Link", "Content-Disposition", "Idempotent-Replayed"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
+	router.Use(middlewares.RequestMetaMiddleware())
+	router.Use(middlewares.ErrorMiddleware())
 
 	auth := c.AuthMiddleware()
-	admin := c.AdminMiddleware()
-	teacherAdmin := c.TeacherAdminMiddleware()
+	can := c.PermissionMiddleware
+	// retried writes with the same Idempotency-Key are answered from the
+	// first response
+	idem := c.IdempotencyMiddleware()
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	router.POST("/api/create-user", auth, admin, c.UserController.CreateUser)
+	v1 := router.Group("/api/v1")
 
-	router.GET("/api/read-all-students", auth, admin, c.StudentController.ReadAllStudents)
-	router.GET("/api/read-all-students-by-group-id", auth, c.StudentController.ReadAllStudentsByGroupId)
-	router.GET("/api/read-student", auth, c.StudentController.ReadStudent)
-	router.PUT("/api/update-student", auth, c.StudentController.UpdateStudent)
-	router.DELETE("/api/delete-student", auth, admin, c.StudentController.DeleteStudent)
+	v1.POST("/login", c.AuthController.Login)
+	v1.POST("/refresh", c.AuthController.RefreshTokens)
+	v1.POST("/password-reset/request", c.AuthController.RequestPasswordReset)
+	v1.POST("/password-reset/confirm", c.AuthController.ConfirmPasswordReset)
 
-	router.GET("/api/read-all-teachers", auth, admin, c.TeacherController.ReadAllTeachers)
-	router.GET("/api/read-teacher", auth, teacherAdmin, c.TeacherController.ReadTeacher)
-	router.PUT("/api/update-teacher", auth, teacherAdmin, c.TeacherController.UpdateTeacher)
-	router.DELETE("/api/delete-teacher", auth, admin, c.TeacherController.DeleteTeacher)
+	v1.POST("/logout", auth, idem, c.AuthController.Logout)
+	v1.GET("/me", auth, c.UserController.ReadMe)
+	v1.PUT("/me", auth, idem, c.UserController.UpdateMe)
+	v1.PATCH("/me", auth, idem, c.UserController.PatchMe)
+	v1.PUT("/me/password", auth, idem, c.UserController.ChangePassword)
 
-	router.GET("/api/read-admin", auth, admin, c.AdminController.ReadAdmin)
-	router.PUT("/api/update-admin", auth, admin, c.AdminController.UpdateAdmin)
-	router.DELETE("/api/delete-admin", auth, admin, c.AdminController.DeleteAdmin)
+	v1.POST("/users", auth, can(entities.UsersCreatePermission), idem, c.UserController.CreateUser)
+	v1.POST("/users/import", auth, can(entities.UsersCreatePermission), idem, c.UserController.ImportUsers)
+	v1.POST("/users/:id/revoke-sessions", auth, can(entities.UsersRevokeSessionsPermission), idem, c.UserController.RevokeSessions)
+	v1.POST("/users/:id/reset-password", auth, can(entities.UsersResetPasswordPermission), idem, c.UserController.ResetPassword)
 
-	router.POST("/api/create-group", auth, admin, c.GroupController.CreateGroup)
-	router.GET("/api/read-all-groups", auth, admin, c.GroupController.ReadAllGroups)
-	router.GET("/api/read-group", auth, c.GroupController.ReadGroup)
-	router.PUT("/api/update-group", auth, admin, c.GroupController.UpdateGroup)
-	router.DELETE("/api/delete-group", auth, admin, c.GroupController.DeleteGroup)
+	v1.GET("/lockouts", auth, can(entities.LockoutsReadPermission), c.LockoutController.ReadLockouts)
+	v1.POST("/lockouts/unlock", auth, can(entities.LockoutsUnlockPermission), idem, c.LockoutController.Unlock)
+
+	v1.GET("/roles", auth, can(entities.RolesManagePermission), c.RoleController.ReadRoles)
+	v1.POST("/roles", auth, can(entities.RolesManagePermission), idem, c.RoleController.CreateRole)
+	v1.PUT("/roles/:name/permissions", auth, can(entities.RolesManagePermission), idem, c.RoleController.UpdateRoleGrants)
+
+	v1.GET("/audit", auth, can(entities.AuditReadPermission), c.AuditController.ReadAuditLog)
+
+	v1.GET("/trash/:type", auth, can(entities.TrashManagePermission), c.TrashController.ReadDeleted)
+	v1.POST("/trash/:type/:id/restore", auth, can(entities.TrashManagePermission), idem, c.TrashController.RestoreDeleted)
+	v1.DELETE("/trash/:type/:id", auth, can(entities.TrashPurgePermission), idem, c.TrashController.PurgeDeleted)
+
+	v1.GET("/search", auth, c.SearchController.Search)
+
+	v1.GET("/students", auth, can(entities.StudentsReadPermission), c.StudentController.ReadAllStudents)
+	v1.GET("/students/export", auth, can(entities.StudentsReadPermission), c.ExportController.ExportStudents)
+	v1.POST("/students/bulk/assign-group", auth, idem, c.StudentController.BulkAssignGroup)
+	v1.POST("/students/bulk/delete", auth, can(entities.StudentsDeletePermission), idem, c.StudentController.BulkDelete)
+	v1.POST("/students/bulk/restore", auth, can(entities.TrashManagePermission), idem, c.StudentController.BulkRestore)
+	v1.GET("/students/:id", auth, c.StudentController.ReadStudent)
+	v1.PATCH("/students/:id", auth, idem, c.StudentController.PatchStudent)
+	v1.DELETE("/students/:id", auth, can(entities.StudentsDeletePermission), idem, c.StudentController.DeleteStudent)
+
+	v1.GET("/teachers", auth, can(entities.TeachersReadPermission), c.TeacherController.ReadAllTeachers)
+	v1.GET("/teachers/export", auth, can(entities.TeachersReadPermission), c.ExportController.ExportTeachers)
+	v1.GET("/teachers/:id", auth, c.TeacherController.ReadTeacher)
+	v1.PATCH("/teachers/:id", auth, idem, c.TeacherController.PatchTeacher)
+	v1.DELETE("/teachers/:id", auth, can(entities.TeachersDeletePermission), idem, c.TeacherController.DeleteTeacher)
+
+	v1.GET("/admins/:id", auth, can(entities.AdminsReadPermission), c.AdminController.ReadAdmin)
+	v1.PATCH("/admins/:id", auth, can(entities.AdminsUpdatePermission), idem, c.AdminController.PatchAdmin)
+	v1.DELETE("/admins/:id", auth, can(entities.AdminsDeletePermission), idem, c.AdminController.DeleteAdmin)
+
+	v1.POST("/groups", auth, can(entities.GroupsCreatePermission), idem, c.GroupController.CreateGroup)
+	v1.GET("/groups", auth, can(entities.GroupsReadPermission), c.GroupController.ReadAllGroups)
+	v1.GET("/groups/export", auth, can(entities.GroupsReadPermission), c.ExportController.ExportGroups)
+	v1.GET("/groups/:id", auth, c.GroupController.ReadGroup)
+	v1.GET("/groups/:id/students", auth, c.StudentController.ReadAllStudentsByGroupId)
+	v1.GET("/groups/:id/students/export", auth, c.ExportController.ExportGroupStudents)
+	v1.PATCH("/groups/:id", auth, can(entities.GroupsUpdatePermission), idem, c.GroupController.PatchGroup)
+	v1.DELETE("/groups/:id", auth, can(entities.GroupsDeletePermission), idem, c.GroupController.DeleteGroup)
+
+	// the routes the clients used before /api/v1, kept until the sunset date
+	legacy := c.DeprecationMiddleware
+
+	router.POST("/api/login", legacy("/api/v1/login"), c.AuthController.Login)
+	router.POST("/api/refresh", legacy("/api/v1/refresh"), c.AuthController.RefreshTokens)
+	router.POST("/api/password-reset/request", legacy("/api/v1/password-reset/request"), c.AuthController.RequestPasswordReset)
+	router.POST("/api/password-reset/confirm", legacy("/api/v1/password-reset/confirm"), c.AuthController.ConfirmPasswordReset)
+
+	router.POST("/api/logout", legacy("/api/v1/logout"), auth, idem, c.AuthController.Logout)
+	router.GET("/api/me", legacy("/api/v1/me"), auth, c.UserController.ReadMe)
+	router.PUT("/api/me", legacy("/api/v1/me"), auth, idem, c.UserController.UpdateMe)
+	router.PATCH("/api/me", legacy("/api/v1/me"), auth, idem, c.UserController.PatchMe)
+	router.PUT("/api/me/password", legacy("/api/v1/me/password"), auth, idem, c.UserController.ChangePassword)
+
+	router.POST("/api/create-user", legacy("/api/v1/users"), auth, can(entities.UsersCreatePermission), idem, c.UserController.CreateUser)
+	router.POST("/api/users/:id/revoke-sessions", legacy("/api/v1/users/:id/revoke-sessions"), auth, can(entities.UsersRevokeSessionsPermission), idem, c.UserController.RevokeSessions)
+	router.POST("/api/users/:id/reset-password", legacy("/api/v1/users/:id/reset-password"), auth, can(entities.UsersResetPasswordPermission), idem, c.UserController.ResetPassword)
+
+	router.GET("/api/lockouts", legacy("/api/v1/lockouts"), auth, can(entities.LockoutsReadPermission), c.LockoutController.ReadLockouts)
+	router.POST("/api/lockouts/unlock", legacy("/api/v1/lockouts/unlock"), auth, can(entities.LockoutsUnlockPermission), idem, c.LockoutController.Unlock)
+
+	router.GET("/api/roles", legacy("/api/v1/roles"), auth, can(entities.RolesManagePermission), c.RoleController.ReadRoles)
+	router.POST("/api/roles", legacy("/api/v1/roles"), auth, can(entities.RolesManagePermission), idem, c.RoleController.CreateRole)
+	router.PUT("/api/roles/:name/permissions", legacy("/api/v1/roles/:name/permissions"), auth, can(entities.RolesManagePermission), idem, c.RoleController.UpdateRoleGrants)
+
+	router.GET("/api/audit", legacy("/api/v1/audit"), auth, can(entities.AuditReadPermission), c.AuditController.ReadAuditLog)
+
+	router.GET("/api/trash/:type", legacy("/api/v1/trash/:type"), auth, can(entities.TrashManagePermission), c.TrashController.ReadDeleted)
+	router.POST("/api/trash/:type/:id/restore", legacy("/api/v1/trash/:type/:id/restore"), auth, can(entities.TrashManagePermission), idem, c.TrashController.RestoreDeleted)
+	router.DELETE("/api/trash/:type/:id", legacy("/api/v1/trash/:type/:id"), auth, can(entities.TrashPurgePermission), idem, c.TrashController.PurgeDeleted)
+
+	router.GET("/api/read-all-students", legacy("/api/v1/students"), auth, can(entities.StudentsReadPermission), c.StudentController.ReadAllStudents)
+	router.GET("/api/read-all-students-by-group-id", legacy("/api/v1/groups/:id/students"), auth, c.StudentController.ReadAllStudentsByGroupId)
+	router.GET("/api/read-student", legacy("/api/v1/students/:id"), auth, c.StudentController.ReadStudent)
+	router.PUT("/api/update-student", legacy("/api/v1/students/:id"), auth, idem, c.StudentController.UpdateStudent)
+	router.PATCH("/api/students/:id", legacy("/api/v1/students/:id"), auth, idem, c.StudentController.PatchStudent)
+	router.DELETE("/api/delete-student", legacy("/api/v1/students/:id"), auth, can(entities.StudentsDeletePermission), idem, c.StudentController.DeleteStudent)
+
+	router.GET("/api/read-all-teachers", legacy("/api/v1/teachers"), auth, can(entities.TeachersReadPermission), c.TeacherController.ReadAllTeachers)
+	router.GET("/api/read-teacher", legacy("/api/v1/teachers/:id"), auth, c.TeacherController.ReadTeacher)
+	router.PUT("/api/update-teacher", legacy("/api/v1/teachers/:id"), auth, idem, c.TeacherController.UpdateTeacher)
+	router.PATCH("/api/teachers/:id", legacy("/api/v1/teachers/:id"), auth, idem, c.TeacherController.PatchTeacher)
+	router.DELETE("/api/delete-teacher", legacy("/api/v1/teachers/:id"), auth, can(entities.TeachersDeletePermission), idem, c.TeacherController.DeleteTeacher)
+
+	router.GET("/api/read-admin", legacy("/api/v1/admins/:id"), auth, can(entities.AdminsReadPermission), c.AdminController.ReadAdmin)
+	router.PUT("/api/update-admin", legacy("/api/v1/admins/:id"), auth, can(entities.AdminsUpdatePermission), idem, c.AdminController.UpdateAdmin)
+	router.PATCH("/api/admins/:id", legacy("/api/v1/admins/:id"), auth, can(entities.AdminsUpdatePermission), idem, c.AdminController.PatchAdmin)
+	router.DELETE("/api/delete-admin", legacy("/api/v1/admins/:id"), auth, can(entities.AdminsDeletePermission), idem, c.AdminController.DeleteAdmin)
+
+	router.POST("/api/create-group", legacy("/api/v1/groups"), auth, can(entities.GroupsCreatePermission), idem, c.GroupController.CreateGroup)
+	router.GET("/api/read-all-groups", legacy("/api/v1/groups"), auth, can(entities.GroupsReadPermission), c.GroupController.ReadAllGroups)
+	router.GET("/api/read-group", legacy("/api/v1/groups/:id"), auth, c.GroupController.ReadGroup)
+	router.PUT("/api/update-group", legacy("/api/v1/groups/:id"), auth, can(entities.GroupsUpdatePermission), idem, c.GroupController.UpdateGroup)
+	router.PATCH("/api/groups/:id", legacy("/api/v1/groups/:id"), auth, can(entities.GroupsUpdatePermission), idem, c.GroupController.PatchGroup)
+	router.DELETE("/api/delete-group", legacy("/api/v1/groups/:id"), auth, can(entities.GroupsDeletePermission), idem, c.GroupController.DeleteGroup)
 
 	return router
 }
@@ -0,0 +1,140 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Resource describes what a permission is checked against. OwnerId is the user
+// the record belongs to and GroupId the group it is part of; a zero value
+// means "not applicable", so an empty Resource is only matched by a grant with
+// the "all" scope.
+type Resource struct {
+	OwnerId int
+	GroupId int
+}
+
+// AccessControl decides whether a user may perform an action. The role to
+// permission mapping lives in Postgres and is cached in memory; Sync reloads
+// it after an edit and periodically so that other instances pick changes up.
+type AccessControl struct {
+	roleRepo  RoleRepository
+	groupRepo GroupMembershipRepository
+	mu        sync.RWMutex
+	grants    map[string]map[string]entities.Scope
+}
+
+func NewAccessControl(roleRepo RoleRepository, groupRepo GroupMembershipRepository) *AccessControl {
+	return &AccessControl{roleRepo: roleRepo, groupRepo: groupRepo, grants: make(map[string]map[string]entities.Scope)}
+}
+
+// Authorize returns nil if the user holds the permission with a scope covering
+// the resource and AccessDeniedError otherwise.
+func (a *AccessControl) Authorize(ctx context.Context, user entities.User, permission string, resource Resource) error {
+	a.mu.RLock()
+	scope, ok := a.grants[user.Role][permission]
+	a.mu.RUnlock()
+	if !ok {
+		return AccessDeniedError
+	}
+
+	switch scope {
+	case entities.ScopeAll:
+		return nil
+
+	case entities.ScopeSelf:
+		if resource.OwnerId != 0 && resource.OwnerId == user.Id {
+			return nil
+		}
+
+	case entities.ScopeGroup:
+		if resource.OwnerId != 0 && resource.OwnerId == user.Id {
+			return nil
+		}
+		if resource.GroupId == 0 {
+			return AccessDeniedError
+		}
+
+		groupIds, err := a.groupRepo.ReadIdsByMember(ctx, user.Id)
+		if err != nil {
+			return ReadError
+		}
+		for _, id := range groupIds {
+			if id == resource.GroupId {
+				return nil
+			}
+		}
+	}
+
+	return AccessDeniedError
+}
+
+// Visibility returns the records the user may read with the permission, by
+// the same scope rules as Authorize: the "group" scope covers the user's own
+// record and the ones in their groups. A user without the permission sees
+// nothing.
+func (a *AccessControl) Visibility(ctx context.Context, user entities.User, permission string) (entities.Visibility, error) {
+	a.mu.RLock()
+	scope, ok := a.grants[user.Role][permission]
+	a.mu.RUnlock()
+	if !ok {
+		return entities.Visibility{}, nil
+	}
+
+	switch scope {
+	case entities.ScopeAll:
+		return entities.Visibility{All: true}, nil
+
+	case entities.ScopeSelf:
+		return entities.Visibility{OwnerId: user.Id}, nil
+
+	case entities.ScopeGroup:
+		groupIds, err := a.groupRepo.ReadIdsByMember(ctx, user.Id)
+		if err != nil {
+			return entities.Visibility{}, ReadError
+		}
+		return entities.Visibility{OwnerId: user.Id, GroupIds: groupIds}, nil
+	}
+
+	return entities.Visibility{}, nil
+}
+
+func (a *AccessControl) Sync(ctx context.Context) error {
+	roles, err := a.roleRepo.ReadAll(ctx)
+	if err != nil {
+		return ReadError
+	}
+
+	grants := make(map[string]map[string]entities.Scope, len(roles))
+	for _, role := range roles {
+		grants[role.Name] = make(map[string]entities.Scope, len(role.Grants))
+		for _, grant := range role.Grants {
+			grants[role.Name][grant.Permission] = grant.Scope
+		}
+	}
+
+	a.mu.Lock()
+	a.grants = grants
+	a.mu.Unlock()
+
+	return nil
+}
+
+func (a *AccessControl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := a.Sync(ctx); err != nil {
+				fmt.Println("failed to sync role permissions:", err)
+			}
+		}
+	}
+}
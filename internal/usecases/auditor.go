@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Auditor appends entries to the audit log. It has to be called with the
+// context of the transaction making the change, so that the entry is only
+// stored if the change is.
+type Auditor struct {
+	repo AuditRepository
+}
+
+func NewAuditor(repo AuditRepository) *Auditor {
+	return &Auditor{repo: repo}
+}
+
+// Record stores the change of the entity. before and after are the entity
+// (or any JSON-serialisable view of it) before and after the change; nil
+// stands for "did not exist". Only the fields that differ are kept.
+func (a *Auditor) Record(ctx context.Context, action, entityType string, entityId any, before, after any) error {
+	beforeDiff, afterDiff, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+
+	meta := RequestMetaFromContext(ctx)
+	err = a.repo.Create(ctx, entities.AuditEntry{
+		ActorId:    meta.UserId,
+		ActorRole:  meta.Role,
+		Action:     action,
+		EntityType: entityType,
+		EntityId:   fmt.Sprint(entityId),
+		Before:     beforeDiff,
+		After:      afterDiff,
+		RequestId:  meta.RequestId,
+		Ip:         meta.Ip,
+	})
+	if err != nil {
+		return CreateError
+	}
+
+	return nil
+}
+
+func auditDiff(before, after any) (json.RawMessage, json.RawMessage, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for key, value := range beforeFields {
+			if other, ok := afterFields[key]; ok && reflect.DeepEqual(value, other) {
+				delete(beforeFields, key)
+				delete(afterFields, key)
+			}
+		}
+	}
+
+	beforeJSON, err := marshalAuditFields(beforeFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := marshalAuditFields(afterFields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return beforeJSON, afterJSON, nil
+}
+
+func auditFields(value any) (map[string]any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit data: %w", err)
+	}
+
+	fields := map[string]any{}
+	err = json.Unmarshal(raw, &fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit data: %w", err)
+	}
+
+	return fields, nil
+}
+
+func marshalAuditFields(fields map[string]any) (json.RawMessage, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	return json.Marshal(fields)
+}
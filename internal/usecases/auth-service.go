@@ -2,21 +2,71 @@ package usecases
 
 import (
 	"backendForKeenEye/internal/entities"
+	jwtService "backendForKeenEye/pkg/jwt-service"
 	"context"
+	"errors"
 	"fmt"
 )
 
 type AuthService struct {
-	userRepo    ReadUserRepository
+	userRepo    AuthUserRepository
 	studentRepo ReadStudentRepository
 	teacherRepo ReadTeacherRepository
 	adminRepo   ReadAdminRepository
 	encryption  Cryptographer
 	jwt         JWTGenerator
+	denylist    AccessTokenDenylist
+	throttler   *LoginThrottler
 }
 
-func NewAuthService(userRepo ReadUserRepository, studentRepo ReadStudentRepository, teacherRepo ReadTeacherRepository, adminRepo ReadAdminRepository, encryption Cryptographer, jwt JWTGenerator) *AuthService {
-	return &AuthService{userRepo: userRepo, studentRepo: studentRepo, teacherRepo: teacherRepo, adminRepo: adminRepo, encryption: encryption, jwt: jwt}
+func NewAuthService(userRepo AuthUserRepository, studentRepo ReadStudentRepository, teacherRepo ReadTeacherRepository, adminRepo ReadAdminRepository, encryption Cryptographer, jwt JWTGenerator, denylist AccessTokenDenylist, throttler *LoginThrottler) *AuthService {
+	return &AuthService{userRepo: userRepo, studentRepo: studentRepo, teacherRepo: teacherRepo, adminRepo: adminRepo, encryption: encryption, jwt: jwt, denylist: denylist, throttler: throttler}
+}
+
+// Authenticate checks login and password like GetUserByLoginAndPassword, but
+// refuses to even try while the login or the client address is throttled,
+// and counts the failures.
+func (a *AuthService) Authenticate(ctx context.Context, login, password, ip string) (entities.User, error) {
+	if err := a.throttler.Check(ctx, login, ip); err != nil {
+		return entities.User{}, err
+	}
+
+	user, err := a.GetUserByLoginAndPassword(ctx, login, password)
+	if err != nil {
+		if throttleErr := a.throttler.RegisterFailure(ctx, login, ip); throttleErr != nil {
+			fmt.Println("failed to register login failure:", throttleErr)
+		}
+		return entities.User{}, err
+	}
+
+	if throttleErr := a.throttler.RegisterSuccess(ctx, login); throttleErr != nil {
+		fmt.Println("failed to reset login failures:", throttleErr)
+	}
+
+	return user, nil
+}
+
+// AuthenticateToken is GetUserByAccessToken with the same per-address
+// throttling as Authenticate, so that tokens can't be guessed either. Only
+// tokens we didn't sign count as failures: expired, revoked and outdated
+// tokens are what every client presents sooner or later.
+func (a *AuthService) AuthenticateToken(ctx context.Context, token, ip string) (entities.User, error) {
+	if err := a.throttler.Check(ctx, "", ip); err != nil {
+		return entities.User{}, err
+	}
+
+	user, err := a.GetUserByAccessToken(ctx, token)
+	if errors.Is(err, MalformedTokenError) {
+		if throttleErr := a.throttler.RegisterFailure(ctx, "", ip); throttleErr != nil {
+			fmt.Println("failed to register token failure:", throttleErr)
+		}
+		return entities.User{}, err
+	}
+	if err != nil {
+		return entities.User{}, err
+	}
+
+	return user, nil
 }
 
 func (a *AuthService) GetUserByLoginAndPassword(ctx context.Context, login, password string) (entities.User, error) {
@@ -30,18 +80,35 @@ func (a *AuthService) GetUserByLoginAndPassword(ctx context.Context, login, pass
 		return entities.User{}, DifferentPasswordError
 	}
 
+	// The plain password is only available here, so this is where hashes made
+	// with an outdated algorithm or cost are upgraded.
+	if a.encryption.NeedsRehash(user.Password, user.Salt) {
+		if hashedPassword, salt, err := a.encryption.HashPassword(password); err == nil {
+			if err = a.userRepo.UpdatePassword(ctx, user.Id, hashedPassword, salt, user.MustChangePassword); err == nil {
+				user.Password, user.Salt = hashedPassword, salt
+			}
+		}
+	}
+
 	return user, nil
 }
 
 func (a *AuthService) GetUserByAccessToken(ctx context.Context, token string) (entities.User, error) {
-	dataFromToken, err := a.jwt.ParseJWT(token)
+	dataFromToken, err := a.jwt.ParseAccessJWT(token)
+	if errors.Is(err, jwtService.LifetimeIsOverError) {
+		return entities.User{}, ExpiredTokenError
+	}
 	if err != nil {
-		return entities.User{}, fmt.Errorf("invalid token: %w", err)
+		return entities.User{}, fmt.Errorf("%w: %w", MalformedTokenError, err)
 	}
 
 	id, ok := dataFromToken["sub"].(float64)
 	if !ok {
-		return entities.User{}, fmt.Errorf("invalid token payload")
+		return entities.User{}, fmt.Errorf("%w: no subject", MalformedTokenError)
+	}
+
+	if jti, _ := dataFromToken["jti"].(string); a.denylist.IsRevoked(jti) {
+		return entities.User{}, InvalidTokenError
 	}
 
 	user, err := a.userRepo.ReadById(ctx, int(id))
@@ -49,6 +116,10 @@ func (a *AuthService) GetUserByAccessToken(ctx context.Context, token string) (e
 		return entities.User{}, UserNotFoundError
 	}
 
+	if version, _ := dataFromToken["ver"].(float64); int(version) != user.TokenVersion {
+		return entities.User{}, InvalidTokenError
+	}
+
 	return user, nil
 }
 
@@ -78,3 +149,18 @@ func (a *AuthService) GetAdminById(ctx context.Context, id int) (entities.Admin,
 
 	return admin, nil
 }
+
+// GetProfile returns the role specific record of the user, or nil for roles
+// that have no profile table.
+func (a *AuthService) GetProfile(ctx context.Context, user entities.User) (any, error) {
+	switch user.Role {
+	case "student":
+		return a.GetStudentById(ctx, user.Id)
+	case "teacher":
+		return a.GetTeacherById(ctx, user.Id)
+	case "admin":
+		return a.GetAdminById(ctx, user.Id)
+	}
+
+	return nil, nil
+}
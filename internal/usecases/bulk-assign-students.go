@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+)
+
+type BulkAssignStudentsUsecase struct {
+	studentRepo UpdateStudentRepository
+	groupRepo   ReadGroupRepository
+	access      Authorizer
+	tx          Transactor
+	audit       AuditRecorder
+	limit       int
+}
+
+type BulkAssignStudentsRequestDto struct {
+	User    entities.User
+	GroupId int
+	BulkRequest
+}
+
+func NewBulkAssignStudentsUsecase(studentRepo UpdateStudentRepository, groupRepo ReadGroupRepository, access Authorizer, tx Transactor, audit AuditRecorder, limit int) BulkAssignStudentsUsecase {
+	return BulkAssignStudentsUsecase{studentRepo: studentRepo, groupRepo: groupRepo, access: access, tx: tx, audit: audit, limit: limit}
+}
+
+// BulkAssignStudents moves the students into the group on behalf of
+// request.User, who needs students.transfer on the group and on the current
+// group of each student, as for a single move. Students already in the group
+// are left as they are.
+func (uc *BulkAssignStudentsUsecase) BulkAssignStudents(ctx context.Context, request BulkAssignStudentsRequestDto) (BulkResponseDto, error) {
+	// authorized first, so that whether the group exists is only told to
+	// users who may move students into it
+	err := uc.access.Authorize(ctx, request.User, entities.StudentsTransferPermission, Resource{GroupId: request.GroupId})
+	if err != nil {
+		return BulkResponseDto{}, TransferForbiddenError
+	}
+
+	_, err = uc.groupRepo.ReadById(ctx, request.GroupId)
+	if errors.Is(err, entities.RecordNotFoundError) {
+		return BulkResponseDto{}, GroupNotFoundError
+	}
+	if err != nil {
+		return BulkResponseDto{}, ReadError
+	}
+
+	return runBulk(ctx, uc.tx, request.BulkRequest, uc.limit, func(ctx context.Context, id int) error {
+		current, err := uc.studentRepo.ReadById(ctx, id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return NotFoundError
+		}
+		if err != nil {
+			return ReadError
+		}
+		if current.GroupId == request.GroupId {
+			return nil
+		}
+
+		err = uc.access.Authorize(ctx, request.User, entities.StudentsTransferPermission, Resource{GroupId: current.GroupId})
+		if err != nil {
+			return TransferForbiddenError
+		}
+
+		student, err := uc.studentRepo.Update(ctx, id, current.Version, map[string]any{"group_id": request.GroupId})
+		if err != nil {
+			return versionedWriteError(err, UpdateError)
+		}
+
+		return uc.audit.Record(ctx, entities.AuditUpdate, entities.AuditEntityStudent, id, current, student)
+	})
+}
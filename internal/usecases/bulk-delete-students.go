@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+)
+
+type BulkDeleteStudentsUsecase struct {
+	studentRepo DeleteStudentRepository
+	tx          Transactor
+	audit       AuditRecorder
+	limit       int
+}
+
+type BulkDeleteStudentsRequestDto struct {
+	BulkRequest
+}
+
+func NewBulkDeleteStudentsUsecase(studentRepo DeleteStudentRepository, tx Transactor, audit AuditRecorder, limit int) BulkDeleteStudentsUsecase {
+	return BulkDeleteStudentsUsecase{studentRepo: studentRepo, tx: tx, audit: audit, limit: limit}
+}
+
+// BulkDeleteStudents moves the students to the trash, from where they can be
+// restored until they are purged.
+func (uc *BulkDeleteStudentsUsecase) BulkDeleteStudents(ctx context.Context, request BulkDeleteStudentsRequestDto) (BulkResponseDto, error) {
+	return runBulk(ctx, uc.tx, request.BulkRequest, uc.limit, func(ctx context.Context, id int) error {
+		before, err := uc.studentRepo.ReadById(ctx, id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return NotFoundError
+		}
+		if err != nil {
+			return ReadError
+		}
+
+		err = uc.studentRepo.SoftDelete(ctx, id, before.Version)
+		if err != nil {
+			return versionedWriteError(err, DeleteError)
+		}
+
+		return uc.audit.Record(ctx, entities.AuditDelete, entities.AuditEntityStudent, id, before, nil)
+	})
+}
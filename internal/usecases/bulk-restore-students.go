@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+)
+
+type BulkRestoreStudentsUsecase struct {
+	trashRepo TrashRepository
+	tx        Transactor
+	audit     AuditRecorder
+	limit     int
+}
+
+type BulkRestoreStudentsRequestDto struct {
+	BulkRequest
+}
+
+func NewBulkRestoreStudentsUsecase(trashRepo TrashRepository, tx Transactor, audit AuditRecorder, limit int) BulkRestoreStudentsUsecase {
+	return BulkRestoreStudentsUsecase{trashRepo: trashRepo, tx: tx, audit: audit, limit: limit}
+}
+
+// BulkRestoreStudents takes soft-deleted students out of the trash.
+func (uc *BulkRestoreStudentsUsecase) BulkRestoreStudents(ctx context.Context, request BulkRestoreStudentsRequestDto) (BulkResponseDto, error) {
+	return runBulk(ctx, uc.tx, request.BulkRequest, uc.limit, func(ctx context.Context, id int) error {
+		record, err := uc.trashRepo.ReadDeletedById(ctx, entities.AuditEntityStudent, id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return RecordNotFoundError
+		}
+		if err != nil {
+			return ReadError
+		}
+
+		err = uc.trashRepo.Restore(ctx, entities.AuditEntityStudent, id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return RecordNotFoundError
+		}
+		if err != nil {
+			return repositoryError(err, UpdateError)
+		}
+
+		before := map[string]any{"is_deleted": true, "deleted_at": record.DeletedAt}
+		after := map[string]any{"is_deleted": false, "deleted_at": nil}
+		return uc.audit.Record(ctx, entities.AuditRestore, entities.AuditEntityStudent, id, before, after)
+	})
+}
@@ -0,0 +1,103 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Statuses of the items of a bulk operation.
+const (
+	BulkItemDone       = "done"
+	BulkItemFailed     = "failed"
+	BulkItemRolledBack = "rolled_back"
+)
+
+// errBulkFailed rolls an atomic bulk operation back when an item failed.
+var errBulkFailed = errors.New("bulk item failed")
+
+// BulkRequest lists the records a bulk operation applies to. With Atomic set
+// it is applied to all of them or, if any fails, to none.
+type BulkRequest struct {
+	Ids    []int
+	Atomic bool
+}
+
+type BulkItemResult struct {
+	Id      int                `json:"id"`
+	Status  string             `json:"status"`
+	Code    entities.ErrorCode `json:"code,omitempty"`
+	Message string             `json:"message,omitempty"`
+}
+
+type BulkResponseDto struct {
+	Committed bool             `json:"committed"`
+	Done      int              `json:"done"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// runBulk applies op to every id in a single transaction, each item in a
+// savepoint so that a failed one leaves the others applied. Items failing
+// with a domain error are reported; any other error, such as a lost
+// connection, fails the whole operation.
+func runBulk(ctx context.Context, tx Transactor, request BulkRequest, limit int, op func(ctx context.Context, id int) error) (BulkResponseDto, error) {
+	var response BulkResponseDto
+
+	if len(request.Ids) == 0 {
+		return response, ValidationError.WithFields(entities.FieldError{Field: "ids", Message: "must not be empty"})
+	}
+	if limit > 0 && len(request.Ids) > limit {
+		return response, ValidationError.WithFields(entities.FieldError{Field: "ids", Message: fmt.Sprintf("must contain at most %d items", limit)})
+	}
+	seen := make(map[int]bool, len(request.Ids))
+	for _, id := range request.Ids {
+		if seen[id] {
+			return response, ValidationError.WithFields(entities.FieldError{Field: "ids", Message: fmt.Sprintf("repeats id %d", id)})
+		}
+		seen[id] = true
+	}
+
+	err := tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		response.Results = make([]BulkItemResult, 0, len(request.Ids))
+		for _, id := range request.Ids {
+			err := tx.WithinTransaction(ctx, func(ctx context.Context) error {
+				return op(ctx, id)
+			})
+
+			code := entities.CodeOf(err)
+			switch {
+			case err == nil:
+				response.Results = append(response.Results, BulkItemResult{Id: id, Status: BulkItemDone})
+				response.Done++
+			case code != entities.InternalCode:
+				response.Results = append(response.Results, BulkItemResult{Id: id, Status: BulkItemFailed, Code: code, Message: err.Error()})
+				response.Failed++
+			default:
+				return err
+			}
+		}
+
+		if request.Atomic && response.Failed > 0 {
+			return errBulkFailed
+		}
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, errBulkFailed):
+		for i := range response.Results {
+			if response.Results[i].Status == BulkItemDone {
+				response.Results[i].Status = BulkItemRolledBack
+			}
+		}
+		response.Done = 0
+		return response, nil
+	case err != nil:
+		return BulkResponseDto{}, err
+	}
+
+	response.Committed = true
+	return response, nil
+}
@@ -0,0 +1,60 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"fmt"
+)
+
+type ChangePasswordUsecase struct {
+	userRepo UpdatePasswordRepository
+	crypto   Cryptographer
+	policy   PasswordValidator
+	tx       Transactor
+	audit    AuditRecorder
+}
+
+type ChangePasswordRequestDto struct {
+	UserId          int
+	CurrentPassword string
+	NewPassword     string
+}
+
+func NewChangePasswordUsecase(userRepo UpdatePasswordRepository, crypto Cryptographer, policy PasswordValidator, tx Transactor, audit AuditRecorder) ChangePasswordUsecase {
+	return ChangePasswordUsecase{userRepo: userRepo, crypto: crypto, policy: policy, tx: tx, audit: audit}
+}
+
+func (uc *ChangePasswordUsecase) ChangePassword(ctx context.Context, request ChangePasswordRequestDto) error {
+	if request.NewPassword == request.CurrentPassword {
+		return SamePasswordError
+	}
+
+	user, err := uc.userRepo.ReadById(ctx, request.UserId)
+	if err != nil {
+		return UserNotFoundError
+	}
+
+	_, err = uc.crypto.PasswordComparison(user.Password, request.CurrentPassword, user.Salt)
+	if err != nil {
+		return DifferentPasswordError
+	}
+
+	err = uc.policy.Validate(user.Login, request.NewPassword)
+	if err != nil {
+		return fmt.Errorf("%w: %w", WeakPasswordError, err)
+	}
+
+	hashedPassword, salt, err := uc.crypto.HashPassword(request.NewPassword)
+	if err != nil {
+		return HashPasswordError
+	}
+
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.userRepo.UpdatePassword(ctx, user.Id, hashedPassword, salt, false)
+		if err != nil {
+			return repositoryError(err, UpdateError)
+		}
+
+		return uc.audit.Record(ctx, entities.AuditChangePassword, entities.AuditEntityUser, user.Id, nil, nil)
+	})
+}
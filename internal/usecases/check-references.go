@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+)
+
+// CheckReferencesUsecase tells whether the records a request refers to exist.
+// Soft-deleted records are treated as missing.
+type CheckReferencesUsecase struct {
+	TeacherRepo ReadTeacherRepository
+	GroupRepo   ReadGroupRepository
+}
+
+func NewCheckReferencesUsecase(TeacherRepo ReadTeacherRepository, GroupRepo ReadGroupRepository) CheckReferencesUsecase {
+	return CheckReferencesUsecase{TeacherRepo: TeacherRepo, GroupRepo: GroupRepo}
+}
+
+func (uc *CheckReferencesUsecase) TeacherExists(ctx context.Context, id int) (bool, error) {
+	_, err := uc.TeacherRepo.ReadById(ctx, id)
+	return exists(err)
+}
+
+func (uc *CheckReferencesUsecase) GroupExists(ctx context.Context, id int) (bool, error) {
+	_, err := uc.GroupRepo.ReadById(ctx, id)
+	return exists(err)
+}
+
+func exists(err error) (bool, error) {
+	if errors.Is(err, entities.RecordNotFoundError) {
+		return false, nil
+	}
+	if err != nil {
+		return false, ReadError
+	}
+	return true, nil
+}
@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"fmt"
+)
+
+type ConfirmPasswordResetUsecase struct {
+	resetRepo PasswordResetTokenRepository
+	userRepo  UpdatePasswordRepository
+	sessions  RevokeSessionsUsecase
+	crypto    Cryptographer
+	policy    PasswordValidator
+	tx        Transactor
+	audit     AuditRecorder
+}
+
+type ConfirmPasswordResetRequestDto struct {
+	Token       string
+	NewPassword string
+}
+
+func NewConfirmPasswordResetUsecase(resetRepo PasswordResetTokenRepository, userRepo UpdatePasswordRepository, sessions RevokeSessionsUsecase, crypto Cryptographer, policy PasswordValidator, tx Transactor, audit AuditRecorder) ConfirmPasswordResetUsecase {
+	return ConfirmPasswordResetUsecase{resetRepo: resetRepo, userRepo: userRepo, sessions: sessions, crypto: crypto, policy: policy, tx: tx, audit: audit}
+}
+
+func (uc *ConfirmPasswordResetUsecase) ConfirmPasswordReset(ctx context.Context, request ConfirmPasswordResetRequestDto) error {
+	tokenHash := hashResetToken(request.Token)
+
+	// The password is checked before the token is consumed, so that a
+	// rejected password doesn't burn the token.
+	userId, err := uc.resetRepo.ReadUserId(ctx, tokenHash)
+	if err != nil {
+		return InvalidResetTokenError
+	}
+
+	user, err := uc.userRepo.ReadById(ctx, userId)
+	if err != nil {
+		return UserNotFoundError
+	}
+
+	err = uc.policy.Validate(user.Login, request.NewPassword)
+	if err != nil {
+		return fmt.Errorf("%w: %w", WeakPasswordError, err)
+	}
+
+	hashedPassword, salt, err := uc.crypto.HashPassword(request.NewPassword)
+	if err != nil {
+		return HashPasswordError
+	}
+
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		userId, err := uc.resetRepo.Consume(ctx, tokenHash)
+		if err != nil {
+			return InvalidResetTokenError
+		}
+
+		err = uc.userRepo.UpdatePassword(ctx, userId, hashedPassword, salt, false)
+		if err != nil {
+			return repositoryError(err, UpdateError)
+		}
+
+		err = uc.sessions.RevokeSessions(ctx, RevokeSessionsRequestDto{UserId: userId})
+		if err != nil {
+			return err
+		}
+
+		return uc.audit.Record(ctx, entities.AuditResetPassword, entities.AuditEntityUser, userId, nil, nil)
+	})
+}
@@ -3,41 +3,70 @@ package usecases
 import (
 	"backendForKeenEye/internal/entities"
 	"context"
+	"time"
 )
 
 type Cryptographer interface {
 	HashPassword(password string) (string, string, error)
-	PasswordComparison(password, hashedPassword, salt string) (bool, error)
+	PasswordComparison(hashedPassword, password, salt string) (bool, error)
+	NeedsRehash(hashedPassword, salt string) bool
+}
+
+type PasswordValidator interface {
+	Validate(login, password string) error
+}
+
+type PasswordGenerator interface {
+	GenerateTemporaryPassword() (string, error)
+}
+
+type Notifier interface {
+	SendPasswordResetToken(ctx context.Context, login, email, token string) error
 }
 
 type JWTGenerator interface {
 	GenerateAccessJWT(data map[string]any) (string, error)
 	GenerateRefreshJWT(data map[string]any) (string, error)
-	ParseJWT(tokenString string) (map[string]any, error)
+	ParseAccessJWT(tokenString string) (map[string]any, error)
+	ParseRefreshJWT(tokenString string) (map[string]any, error)
+}
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, login, password, ip string) (entities.User, error)
+	ProfileReader
 }
 
 type ReadAllStudentsRepository interface {
-	Read(ctx context.Context) ([]entities.Student, error)
+	Read(ctx context.Context, list entities.ListQuery) ([]entities.Student, error)
+	Count(ctx context.Context, filter entities.ListFilter) (int, error)
 }
 
 type ReadAllStudentsByGroupIdRepository interface {
-	ReadByGroupId(ctx context.Context, groupId int) ([]entities.Student, error)
+	Read(ctx context.Context, list entities.ListQuery) ([]entities.Student, error)
+	Count(ctx context.Context, filter entities.ListFilter) (int, error)
+}
+
+type ExportStudentsRepository interface {
+	Each(ctx context.Context, list entities.ListQuery, fn func(entities.Student) error) error
 }
 
 type ReadStudentRepository interface {
 	ReadById(ctx context.Context, id int) (entities.Student, error)
+	ReadByIdIncludingDeleted(ctx context.Context, id int) (entities.Student, error)
 }
 
 type UpdateStudentRepository interface {
-	Update(ctx context.Context, id int, updates map[string]any) (entities.Student, error)
+	ReadById(ctx context.Context, id int) (entities.Student, error)
+	Update(ctx context.Context, id int, version int, updates map[string]any) (entities.Student, error)
 }
 
 type DeleteStudentRepository interface {
-	SoftDelete(ctx context.Context, id int) error
+	ReadById(ctx context.Context, id int) (entities.Student, error)
+	SoftDelete(ctx context.Context, id int, version int) error
 }
 
 type CreateUserRepository interface {
-	Create(ctx context.Context, student entities.User) (int, error)
+	Create(ctx context.Context, user entities.User, profile entities.Profile) (int, error)
 }
 
 type ReadUserRepository interface {
@@ -45,50 +74,206 @@ type ReadUserRepository interface {
 	ReadById(ctx context.Context, id int) (entities.User, error)
 }
 
+type AuthUserRepository interface {
+	ReadByLogin(ctx context.Context, login string) (entities.User, error)
+	ReadById(ctx context.Context, id int) (entities.User, error)
+	UpdatePassword(ctx context.Context, id int, password, salt string, mustChangePassword bool) error
+}
+
+type UpdateUserRepository interface {
+	Update(ctx context.Context, id int, version int, updates map[string]any) error
+}
+
+type ProfileReader interface {
+	GetProfile(ctx context.Context, user entities.User) (any, error)
+}
+
+type TokenVersionRepository interface {
+	IncrementTokenVersion(ctx context.Context, id int) error
+}
+
+type UpdatePasswordRepository interface {
+	ReadById(ctx context.Context, id int) (entities.User, error)
+	UpdatePassword(ctx context.Context, id int, password, salt string, mustChangePassword bool) error
+}
+
+type CreateRefreshTokenRepository interface {
+	Create(ctx context.Context, token entities.RefreshToken) error
+}
+
+type RefreshTokenRepository interface {
+	ReadById(ctx context.Context, id string) (entities.RefreshToken, error)
+	Rotate(ctx context.Context, oldId string, newToken entities.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyId string) error
+}
+
+type RevokeRefreshTokensRepository interface {
+	RevokeFamily(ctx context.Context, familyId string) error
+	RevokeByUserId(ctx context.Context, userId int) error
+}
+
+type RevokedTokenRepository interface {
+	Create(ctx context.Context, token entities.RevokedToken) error
+	ReadActive(ctx context.Context) ([]entities.RevokedToken, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+type AccessTokenDenylist interface {
+	Revoke(ctx context.Context, token entities.RevokedToken) error
+	IsRevoked(id string) bool
+}
+
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token entities.PasswordResetToken) (int, error)
+	InvalidateByUserId(ctx context.Context, userId int) error
+	ReadUserId(ctx context.Context, tokenHash string) (int, error)
+	Consume(ctx context.Context, tokenHash string) (int, error)
+}
+
+type IdempotencyKeyRepository interface {
+	Reserve(ctx context.Context, key entities.IdempotencyKey, staleBefore time.Time) (bool, error)
+	Read(ctx context.Context, userId int, key string) (entities.IdempotencyKey, error)
+	Complete(ctx context.Context, key entities.IdempotencyKey) error
+	Delete(ctx context.Context, userId int, key string) error
+	DeleteExpired(ctx context.Context) error
+}
+
+type LoginAttemptRepository interface {
+	Read(ctx context.Context, key string) (entities.LoginAttempt, error)
+	RegisterFailure(ctx context.Context, key string, window time.Duration) (entities.LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+	ReadActive(ctx context.Context, since time.Time) ([]entities.LoginAttempt, error)
+}
+
 type ReadAllTeachersRepository interface {
-	Read(ctx context.Context) ([]entities.Teacher, error)
+	Read(ctx context.Context, list entities.ListQuery) ([]entities.Teacher, error)
+	Count(ctx context.Context, filter entities.ListFilter) (int, error)
+}
+
+type ExportTeachersRepository interface {
+	Each(ctx context.Context, list entities.ListQuery, fn func(entities.Teacher) error) error
 }
 
 type ReadTeacherRepository interface {
 	ReadById(ctx context.Context, id int) (entities.Teacher, error)
+	ReadByIdIncludingDeleted(ctx context.Context, id int) (entities.Teacher, error)
 }
 
 type UpdateTeacherRepository interface {
-	Update(ctx context.Context, id int, updates map[string]any) (entities.Teacher, error)
+	ReadById(ctx context.Context, id int) (entities.Teacher, error)
+	Update(ctx context.Context, id int, version int, updates map[string]any) (entities.Teacher, error)
 }
 
 type DeleteTeacherRepository interface {
-	SoftDelete(ctx context.Context, id int) error
+	ReadById(ctx context.Context, id int) (entities.Teacher, error)
+	SoftDelete(ctx context.Context, id int, version int) error
+}
+
+type TeacherGroupsRepository interface {
+	ReadByTeacherId(ctx context.Context, teacherId int) ([]entities.Group, error)
+	Update(ctx context.Context, id int, version int, updates map[string]any) (entities.Group, error)
+}
+
+type GroupDeleter interface {
+	DeleteGroup(ctx context.Context, request DeleteGroupRequestDto) error
 }
 
 type CreateGroupRepository interface {
 	Create(ctx context.Context, teacher entities.Group) (int, error)
 }
 
+type GroupNamesRepository interface {
+	ReadIdsByNames(ctx context.Context, names []string) (map[string][]int, error)
+}
+
 type ReadAllGroupsRepository interface {
-	Read(ctx context.Context) ([]entities.Group, error)
+	Read(ctx context.Context, list entities.ListQuery) ([]entities.Group, error)
+	Count(ctx context.Context, filter entities.ListFilter) (int, error)
+}
+
+type ExportGroupsRepository interface {
+	Each(ctx context.Context, list entities.ListQuery, fn func(entities.Group) error) error
+	ReadById(ctx context.Context, id int) (entities.Group, error)
 }
 
 type ReadGroupRepository interface {
 	ReadById(ctx context.Context, id int) (entities.Group, error)
+	ReadByIdIncludingDeleted(ctx context.Context, id int) (entities.Group, error)
 }
 
 type UpdateGroupRepository interface {
-	Update(ctx context.Context, id int, updates map[string]any) (entities.Group, error)
+	ReadById(ctx context.Context, id int) (entities.Group, error)
+	Update(ctx context.Context, id int, version int, updates map[string]any) (entities.Group, error)
 }
 
 type DeleteGroupRepository interface {
-	SoftDelete(ctx context.Context, id int) error
+	ReadById(ctx context.Context, id int) (entities.Group, error)
+	SoftDelete(ctx context.Context, id int, version int) error
+}
+
+type GroupStudentsRepository interface {
+	ReadByGroupId(ctx context.Context, groupId int) ([]entities.Student, error)
+	Update(ctx context.Context, id int, version int, updates map[string]any) (entities.Student, error)
+	SoftDelete(ctx context.Context, id int, version int) error
 }
 
 type ReadAdminRepository interface {
 	ReadById(ctx context.Context, id int) (entities.Admin, error)
+	ReadByIdIncludingDeleted(ctx context.Context, id int) (entities.Admin, error)
 }
 
 type UpdateAdminRepository interface {
-	Update(ctx context.Context, id int, updates map[string]any) (entities.Admin, error)
+	ReadById(ctx context.Context, id int) (entities.Admin, error)
+	Update(ctx context.Context, id int, version int, updates map[string]any) (entities.Admin, error)
 }
 
 type DeleteAdminRepository interface {
-	SoftDelete(ctx context.Context, id int) error
+	ReadById(ctx context.Context, id int) (entities.Admin, error)
+	SoftDelete(ctx context.Context, id int, version int) error
+}
+
+type RoleRepository interface {
+	Create(ctx context.Context, role entities.Role) error
+	ReadAll(ctx context.Context) ([]entities.Role, error)
+	ReadPermissions(ctx context.Context) ([]entities.Permission, error)
+	ReplaceGrants(ctx context.Context, role string, grants []entities.Grant) error
+}
+
+type GroupMembershipRepository interface {
+	ReadIdsByMember(ctx context.Context, userId int) ([]int, error)
+}
+
+type Authorizer interface {
+	Authorize(ctx context.Context, user entities.User, permission string, resource Resource) error
+}
+
+type VisibilityResolver interface {
+	Visibility(ctx context.Context, user entities.User, permission string) (entities.Visibility, error)
+}
+
+type SearchRepository interface {
+	SearchStudents(ctx context.Context, query entities.SearchQuery) ([]entities.SearchHit, error)
+	SearchTeachers(ctx context.Context, query entities.SearchQuery) ([]entities.SearchHit, error)
+	SearchGroups(ctx context.Context, query entities.SearchQuery) ([]entities.SearchHit, error)
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry entities.AuditEntry) error
+	Read(ctx context.Context, filter entities.AuditFilter) ([]entities.AuditEntry, error)
+}
+
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type AuditRecorder interface {
+	Record(ctx context.Context, action, entityType string, entityId any, before, after any) error
+}
+
+type TrashRepository interface {
+	ReadDeleted(ctx context.Context, entityType string, before time.Time) ([]entities.DeletedRecord, error)
+	ReadDeletedById(ctx context.Context, entityType string, id int) (entities.DeletedRecord, error)
+	Restore(ctx context.Context, entityType string, id int) error
+	Purge(ctx context.Context, entityType string, id int) error
 }
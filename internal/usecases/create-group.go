@@ -7,6 +7,8 @@ import (
 
 type CreateGroupUsecase struct {
 	GroupRepo CreateGroupRepository
+	tx        Transactor
+	audit     AuditRecorder
 }
 
 type CreateGroupRequestDto struct {
@@ -18,17 +20,27 @@ type CreateGroupResponseDto struct {
 	Id int `json:"id"`
 }
 
-func NewCreateGroupUsecase(GroupRepo CreateGroupRepository) CreateGroupUsecase {
-	return CreateGroupUsecase{GroupRepo: GroupRepo}
+func NewCreateGroupUsecase(GroupRepo CreateGroupRepository, tx Transactor, audit AuditRecorder) CreateGroupUsecase {
+	return CreateGroupUsecase{GroupRepo: GroupRepo, tx: tx, audit: audit}
 }
 
 func (uc *CreateGroupUsecase) CreateGroup(ctx context.Context, request CreateGroupRequestDto) (CreateGroupResponseDto, error) {
 	var response CreateGroupResponseDto
 	student := entities.Group{Name: request.Name, TeacherId: request.TeacherId}
 
-	id, err := uc.GroupRepo.Create(ctx, student)
+	var id int
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		id, err = uc.GroupRepo.Create(ctx, student)
+		if err != nil {
+			return repositoryError(err, CreateError)
+		}
+
+		student.Id = id
+		return uc.audit.Record(ctx, entities.AuditCreate, entities.AuditEntityGroup, id, nil, student)
+	})
 	if err != nil {
-		return response, CreateError
+		return response, err
 	}
 
 	response = CreateGroupResponseDto{
@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+	"regexp"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,19}$`)
+
+type CreateRoleUsecase struct {
+	roleRepo RoleRepository
+	tx       Transactor
+	audit    AuditRecorder
+}
+
+type CreateRoleRequestDto struct {
+	Name        string
+	Description string
+}
+
+func NewCreateRoleUsecase(roleRepo RoleRepository, tx Transactor, audit AuditRecorder) CreateRoleUsecase {
+	return CreateRoleUsecase{roleRepo: roleRepo, tx: tx, audit: audit}
+}
+
+// CreateRole adds a role without any permissions; grants are assigned with
+// UpdateRoleGrantsUsecase.
+func (uc *CreateRoleUsecase) CreateRole(ctx context.Context, request CreateRoleRequestDto) (entities.Role, error) {
+	if !roleNamePattern.MatchString(request.Name) {
+		return entities.Role{}, ValidationError
+	}
+
+	role := entities.Role{Name: request.Name, Description: request.Description, Grants: []entities.Grant{}}
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.roleRepo.Create(ctx, role)
+		if err != nil {
+			if errors.Is(err, entities.DuplicateRoleError) {
+				return RoleExistsError
+			}
+			return CreateError
+		}
+
+		return uc.audit.Record(ctx, entities.AuditCreate, entities.AuditEntityRole, role.Name, nil, role)
+	})
+	if err != nil {
+		return entities.Role{}, err
+	}
+
+	return role, nil
+}
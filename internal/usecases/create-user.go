@@ -3,68 +3,85 @@ package usecases
 import (
 	"backendForKeenEye/internal/entities"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 )
 
 type CreateUserUsecase struct {
 	userRepo CreateUserRepository
 	crypto   Cryptographer
-	jwt      JWTGenerator
+	policy   PasswordValidator
+	tx       Transactor
+	audit    AuditRecorder
 }
 
 type CreateUserRequestDto struct {
 	Login    string
 	Password string
 	Role     string
+	Email    string
+	Profile  entities.Profile
 }
 
 type CreateUserResponseDto struct {
-	Id           int    `json:"id"`
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	Id int `json:"id"`
 }
 
-func NewCreateUserUsecase(userRepo CreateUserRepository, crypto Cryptographer, jwt JWTGenerator) CreateUserUsecase {
-	return CreateUserUsecase{userRepo: userRepo, crypto: crypto, jwt: jwt}
+func NewCreateUserUsecase(userRepo CreateUserRepository, crypto Cryptographer, policy PasswordValidator, tx Transactor, audit AuditRecorder) CreateUserUsecase {
+	return CreateUserUsecase{userRepo: userRepo, crypto: crypto, policy: policy, tx: tx, audit: audit}
 }
 
 func (uc *CreateUserUsecase) CreateUser(ctx context.Context, request CreateUserRequestDto) (CreateUserResponseDto, error) {
 	var response CreateUserResponseDto
-	hashedPassword, salt, err := uc.crypto.HashPassword(request.Password)
-	if err != nil {
-		return response, HashPasswordError
+	request.Login = strings.TrimSpace(request.Login)
+	if request.Login == "" {
+		return response, ValidationError
+	}
+	if request.Profile.GroupId != 0 && request.Role != "student" {
+		return response, ValidationError
 	}
 
-	user := entities.User{Login: request.Login, Password: hashedPassword, Salt: salt, Role: request.Role}
-
-	_, err = user.Validate()
+	err := uc.policy.Validate(request.Login, request.Password)
 	if err != nil {
-		return response, ValidationError
+		return response, fmt.Errorf("%w: %w", WeakPasswordError, err)
 	}
 
-	id, err := uc.userRepo.Create(ctx, user)
+	hashedPassword, salt, err := uc.crypto.HashPassword(request.Password)
 	if err != nil {
-		return response, CreateError
+		return response, HashPasswordError
 	}
 
-	var data = make(map[string]any)
-	data["id"] = id
+	user := entities.User{Login: request.Login, Password: hashedPassword, Salt: salt, Role: request.Role, Email: request.Email}
 
-	accessToken, err := uc.jwt.GenerateAccessJWT(data)
+	_, err = user.Validate()
 	if err != nil {
-		return response, fmt.Errorf("failed to generate access token: %w", err)
+		return response, ValidationError
 	}
 
-	refreshToken, err := uc.jwt.GenerateRefreshJWT(data)
+	var id int
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		id, err = uc.userRepo.Create(ctx, user, request.Profile)
+		if err != nil {
+			switch {
+			case errors.Is(err, entities.DuplicateLoginError):
+				return LoginTakenError
+			case errors.Is(err, entities.UnknownGroupError):
+				return GroupNotFoundError
+			case errors.Is(err, entities.InvalidRoleError):
+				return ValidationError
+			}
+			return CreateError
+		}
+
+		user.Id = id
+		return uc.audit.Record(ctx, entities.AuditCreate, entities.AuditEntityUser, id, nil, map[string]any{"user": user, "profile": request.Profile})
+	})
 	if err != nil {
-		return response, fmt.Errorf("failed to generate refresh token: %w", err)
+		return response, err
 	}
 
-	response = CreateUserResponseDto{
-		Id:           id,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}
+	response = CreateUserResponseDto{Id: id}
 
 	return response, nil
 }
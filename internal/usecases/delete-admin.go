@@ -1,27 +1,44 @@
 package usecases
 
 import (
+	"backendForKeenEye/internal/entities"
 	"context"
+	"errors"
 )
 
 type DeleteAdminUsecase struct {
 	AdminRepo DeleteAdminRepository
+	tx        Transactor
+	audit     AuditRecorder
 }
 
 type DeleteAdminRequestDto struct {
-	Id int
+	Id      int
+	Version int
 }
 
-func NewDeleteAdminUsecase(AdminRepo DeleteAdminRepository) DeleteAdminUsecase {
-	return DeleteAdminUsecase{AdminRepo: AdminRepo}
+func NewDeleteAdminUsecase(AdminRepo DeleteAdminRepository, tx Transactor, audit AuditRecorder) DeleteAdminUsecase {
+	return DeleteAdminUsecase{AdminRepo: AdminRepo, tx: tx, audit: audit}
 }
 
 func (uc *DeleteAdminUsecase) DeleteAdmin(ctx context.Context, request DeleteAdminRequestDto) error {
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := uc.AdminRepo.ReadById(ctx, request.Id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return NotFoundError
+		}
+		if err != nil {
+			return ReadError
+		}
+		if err = checkVersion(request.Version, before.Version); err != nil {
+			return err
+		}
 
-	err := uc.AdminRepo.SoftDelete(ctx, request.Id)
-	if err != nil {
-		return DeleteError
-	}
+		err = uc.AdminRepo.SoftDelete(ctx, request.Id, before.Version)
+		if err != nil {
+			return versionedWriteError(err, DeleteError)
+		}
 
-	return nil
+		return uc.audit.Record(ctx, entities.AuditDelete, entities.AuditEntityAdmin, request.Id, before, nil)
+	})
 }
@@ -1,27 +1,82 @@
 package usecases
 
 import (
+	"backendForKeenEye/internal/entities"
 	"context"
+	"errors"
 )
 
 type DeleteGroupUsecase struct {
-	GroupRepo DeleteGroupRepository
+	GroupRepo   DeleteGroupRepository
+	studentRepo GroupStudentsRepository
+	tx          Transactor
+	audit       AuditRecorder
 }
 
 type DeleteGroupRequestDto struct {
-	Id int
+	Id      int
+	Version int
+	Mode    DeleteMode
 }
 
-func NewDeleteGroupUsecase(GroupRepo DeleteGroupRepository) DeleteGroupUsecase {
-	return DeleteGroupUsecase{GroupRepo: GroupRepo}
+func NewDeleteGroupUsecase(GroupRepo DeleteGroupRepository, studentRepo GroupStudentsRepository, tx Transactor, audit AuditRecorder) DeleteGroupUsecase {
+	return DeleteGroupUsecase{GroupRepo: GroupRepo, studentRepo: studentRepo, tx: tx, audit: audit}
 }
 
+// DeleteGroup soft-deletes the group. Its students are moved out of the group,
+// keep the group from being deleted or are deleted with it, depending on
+// request.Mode.
 func (uc *DeleteGroupUsecase) DeleteGroup(ctx context.Context, request DeleteGroupRequestDto) error {
-
-	err := uc.GroupRepo.SoftDelete(ctx, request.Id)
+	mode, err := deleteModeOrDefault(request.Mode)
 	if err != nil {
-		return DeleteError
+		return err
 	}
 
-	return nil
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := uc.GroupRepo.ReadById(ctx, request.Id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return NotFoundError
+		}
+		if err != nil {
+			return ReadError
+		}
+		if err = checkVersion(request.Version, before.Version); err != nil {
+			return err
+		}
+
+		students, err := uc.studentRepo.ReadByGroupId(ctx, request.Id)
+		if err != nil {
+			return ReadError
+		}
+		if mode == DeleteModeBlock && len(students) > 0 {
+			return HasDependentsError
+		}
+
+		for _, student := range students {
+			if mode == DeleteModeCascade {
+				if err = uc.studentRepo.SoftDelete(ctx, student.Id, student.Version); err != nil {
+					return versionedWriteError(err, DeleteError)
+				}
+				if err = uc.audit.Record(ctx, entities.AuditDelete, entities.AuditEntityStudent, student.Id, student, nil); err != nil {
+					return err
+				}
+				continue
+			}
+
+			after, err := uc.studentRepo.Update(ctx, student.Id, student.Version, map[string]any{"group_id": nil})
+			if err != nil {
+				return versionedWriteError(err, UpdateError)
+			}
+			if err = uc.audit.Record(ctx, entities.AuditUpdate, entities.AuditEntityStudent, student.Id, student, after); err != nil {
+				return err
+			}
+		}
+
+		err = uc.GroupRepo.SoftDelete(ctx, request.Id, before.Version)
+		if err != nil {
+			return versionedWriteError(err, DeleteError)
+		}
+
+		return uc.audit.Record(ctx, entities.AuditDelete, entities.AuditEntityGroup, request.Id, before, nil)
+	})
 }
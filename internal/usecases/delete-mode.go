@@ -0,0 +1,26 @@
+package usecases
+
+// DeleteMode defines what happens to the records that depend on a deleted
+// teacher or group: the groups of the teacher and the students of the group.
+type DeleteMode string
+
+const (
+	// DeleteModeUnassign detaches the dependent records and keeps them.
+	DeleteModeUnassign DeleteMode = "unassign"
+	// DeleteModeBlock refuses the deletion while there are dependent records.
+	DeleteModeBlock DeleteMode = "block"
+	// DeleteModeCascade deletes the dependent records as well.
+	DeleteModeCascade DeleteMode = "cascade"
+)
+
+// deleteModeOrDefault validates the mode; an empty mode means unassign.
+func deleteModeOrDefault(mode DeleteMode) (DeleteMode, error) {
+	switch mode {
+	case "":
+		return DeleteModeUnassign, nil
+	case DeleteModeUnassign, DeleteModeBlock, DeleteModeCascade:
+		return mode, nil
+	default:
+		return "", InvalidDeleteModeError
+	}
+}
@@ -1,27 +1,44 @@
 package usecases
 
 import (
+	"backendForKeenEye/internal/entities"
 	"context"
+	"errors"
 )
 
 type DeleteStudentUsecase struct {
 	StudentRepo DeleteStudentRepository
+	tx          Transactor
+	audit       AuditRecorder
 }
 
 type DeleteStudentRequestDto struct {
-	Id int
+	Id      int
+	Version int
 }
 
-func NewDeleteStudentUsecase(StudentRepo DeleteStudentRepository) DeleteStudentUsecase {
-	return DeleteStudentUsecase{StudentRepo: StudentRepo}
+func NewDeleteStudentUsecase(StudentRepo DeleteStudentRepository, tx Transactor, audit AuditRecorder) DeleteStudentUsecase {
+	return DeleteStudentUsecase{StudentRepo: StudentRepo, tx: tx, audit: audit}
 }
 
 func (uc *DeleteStudentUsecase) DeleteStudent(ctx context.Context, request DeleteStudentRequestDto) error {
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := uc.StudentRepo.ReadById(ctx, request.Id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return NotFoundError
+		}
+		if err != nil {
+			return ReadError
+		}
+		if err = checkVersion(request.Version, before.Version); err != nil {
+			return err
+		}
 
-	err := uc.StudentRepo.SoftDelete(ctx, request.Id)
-	if err != nil {
-		return DeleteError
-	}
+		err = uc.StudentRepo.SoftDelete(ctx, request.Id, before.Version)
+		if err != nil {
+			return versionedWriteError(err, DeleteError)
+		}
 
-	return nil
+		return uc.audit.Record(ctx, entities.AuditDelete, entities.AuditEntityStudent, request.Id, before, nil)
+	})
 }
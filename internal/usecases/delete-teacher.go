@@ -1,27 +1,80 @@
 package usecases
 
 import (
+	"backendForKeenEye/internal/entities"
 	"context"
+	"errors"
 )
 
 type DeleteTeacherUsecase struct {
 	TeacherRepo DeleteTeacherRepository
+	groupRepo   TeacherGroupsRepository
+	groups      GroupDeleter
+	tx          Transactor
+	audit       AuditRecorder
 }
 
 type DeleteTeacherRequestDto struct {
-	Id int
+	Id      int
+	Version int
+	Mode    DeleteMode
 }
 
-func NewDeleteTeacherUsecase(TeacherRepo DeleteTeacherRepository) DeleteTeacherUsecase {
-	return DeleteTeacherUsecase{TeacherRepo: TeacherRepo}
+func NewDeleteTeacherUsecase(TeacherRepo DeleteTeacherRepository, groupRepo TeacherGroupsRepository, groups GroupDeleter, tx Transactor, audit AuditRecorder) DeleteTeacherUsecase {
+	return DeleteTeacherUsecase{TeacherRepo: TeacherRepo, groupRepo: groupRepo, groups: groups, tx: tx, audit: audit}
 }
 
+// DeleteTeacher soft-deletes the teacher. Depending on request.Mode the groups
+// they teach are left without a teacher, keep the teacher from being deleted
+// or are deleted together with their students.
 func (uc *DeleteTeacherUsecase) DeleteTeacher(ctx context.Context, request DeleteTeacherRequestDto) error {
-
-	err := uc.TeacherRepo.SoftDelete(ctx, request.Id)
+	mode, err := deleteModeOrDefault(request.Mode)
 	if err != nil {
-		return DeleteError
+		return err
 	}
 
-	return nil
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := uc.TeacherRepo.ReadById(ctx, request.Id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return NotFoundError
+		}
+		if err != nil {
+			return ReadError
+		}
+		if err = checkVersion(request.Version, before.Version); err != nil {
+			return err
+		}
+
+		groups, err := uc.groupRepo.ReadByTeacherId(ctx, request.Id)
+		if err != nil {
+			return ReadError
+		}
+		if mode == DeleteModeBlock && len(groups) > 0 {
+			return HasDependentsError
+		}
+
+		for _, group := range groups {
+			if mode == DeleteModeCascade {
+				if err = uc.groups.DeleteGroup(ctx, DeleteGroupRequestDto{Id: group.Id, Version: group.Version, Mode: DeleteModeCascade}); err != nil {
+					return err
+				}
+				continue
+			}
+
+			after, err := uc.groupRepo.Update(ctx, group.Id, group.Version, map[string]any{"teacher_id": nil})
+			if err != nil {
+				return versionedWriteError(err, UpdateError)
+			}
+			if err = uc.audit.Record(ctx, entities.AuditUpdate, entities.AuditEntityGroup, group.Id, group, after); err != nil {
+				return err
+			}
+		}
+
+		err = uc.TeacherRepo.SoftDelete(ctx, request.Id, before.Version)
+		if err != nil {
+			return versionedWriteError(err, DeleteError)
+		}
+
+		return uc.audit.Record(ctx, entities.AuditDelete, entities.AuditEntityTeacher, request.Id, before, nil)
+	})
 }
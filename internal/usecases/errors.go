@@ -1,19 +1,81 @@
 package usecases
 
 import (
+	"backendForKeenEye/internal/entities"
 	"errors"
 )
 
 var (
-	UserNotFoundError        = errors.New("account not found")
-	UserAccountNotFoundError = errors.New("user account not found")
-	DifferentPasswordError   = errors.New("passwords are not similar")
+	UserNotFoundError        = entities.NewDomainError(entities.NotFoundCode, "account not found")
+	UserAccountNotFoundError = entities.NewDomainError(entities.NotFoundCode, "user account not found")
+	DifferentPasswordError   = entities.NewDomainError(entities.ForbiddenCode, "current password is incorrect")
 	HashPasswordError        = errors.New("failed to hash password")
 	CreateError              = errors.New("failed to create entity")
 	ReadError                = errors.New("failed to read entity")
 	UpdateError              = errors.New("failed to update entity")
 	DeleteError              = errors.New("failed to delete entity")
-	NoFieldsError            = errors.New("no fields provided to update")
-	MissingIdError           = errors.New("missing id field")
-	ValidationError          = errors.New("validation failed")
+	NoFieldsError            = entities.NewDomainError(entities.ValidationCode, "no fields provided to update")
+	MissingIdError           = entities.NewDomainError(entities.ValidationCode, "missing id field")
+	ValidationError          = entities.NewDomainError(entities.ValidationCode, "validation failed")
+	InvalidCredentialsError  = entities.NewDomainError(entities.UnauthorizedCode, "invalid login or password")
+	GenerateTokenError       = errors.New("failed to generate token")
+	InvalidTokenError        = entities.NewDomainError(entities.UnauthorizedCode, "invalid token")
+	ExpiredTokenError        = entities.NewDomainError(entities.UnauthorizedCode, "token expired")
+	MalformedTokenError      = entities.NewDomainError(entities.UnauthorizedCode, "malformed token")
+	InvalidResetTokenError   = entities.NewDomainError(entities.ValidationCode, "invalid or expired reset token")
+	TokenReusedError         = entities.NewDomainError(entities.UnauthorizedCode, "refresh token reuse detected, session revoked")
+	SamePasswordError        = entities.NewDomainError(entities.ValidationCode, "new password must differ from the current one")
+	WeakPasswordError        = entities.NewDomainError(entities.ValidationCode, "password does not satisfy the password policy")
+	ForbiddenFieldError      = entities.NewDomainError(entities.ForbiddenCode, "field is not editable")
+	LoginTakenError          = entities.NewDomainError(entities.ConflictCode, "login is already taken")
+	GroupNotFoundError       = entities.NewDomainError(entities.ValidationCode, "group not found")
+	AccessDeniedError        = entities.NewDomainError(entities.ForbiddenCode, "access denied")
+	RoleExistsError          = entities.NewDomainError(entities.ConflictCode, "role already exists")
+	RoleNotFoundError        = entities.NewDomainError(entities.NotFoundCode, "role not found")
+	SelfLockoutError         = entities.NewDomainError(entities.ConflictCode, "cannot revoke role management from own role")
+	TransferForbiddenError   = entities.NewDomainError(entities.ForbiddenCode, "moving the student to this group is not allowed")
+	RecordNotFoundError      = entities.NewDomainError(entities.NotFoundCode, "deleted record not found")
+	UnknownEntityTypeError   = entities.NewDomainError(entities.NotFoundCode, "unknown entity type")
+	NotFoundError            = entities.NewDomainError(entities.NotFoundCode, "entity not found")
+	HasDependentsError       = entities.NewDomainError(entities.ConflictCode, "entity has dependent records")
+	InvalidDeleteModeError   = entities.NewDomainError(entities.ValidationCode, "unknown delete mode")
+	VersionMismatchError     = entities.NewDomainError(entities.PreconditionFailedCode, "entity was changed since it was read")
+	InvalidCursorError       = entities.NewDomainError(entities.ValidationCode, "invalid cursor")
+	IdempotencyKeyReuseError = entities.NewDomainError(entities.UnprocessableCode, "idempotency key was already used for a different request")
+	RequestInProgressError   = entities.NewDomainError(entities.ConflictCode, "a request with this idempotency key is still in progress")
 )
+
+// notNullableError reports a patch field that may be changed but not cleared.
+func notNullableError(field string) error {
+	return ValidationError.WithFields(entities.FieldError{Field: field, Message: "cannot be null"})
+}
+
+// checkVersion compares the version the client based its change on with the
+// current one. Zero means the client accepts any version.
+func checkVersion(expected, current int) error {
+	if expected != 0 && expected != current {
+		return VersionMismatchError
+	}
+	return nil
+}
+
+// versionedWriteError is repositoryError for conditional writes: the record
+// was read in the same transaction, so if the write finds no row it was
+// changed concurrently.
+func versionedWriteError(err, fallback error) error {
+	if errors.Is(err, entities.RecordNotFoundError) {
+		return VersionMismatchError
+	}
+	return repositoryError(err, fallback)
+}
+
+// repositoryError keeps the domain errors reported by a repository, such as a
+// missing record or a unique violation, and replaces anything else with
+// fallback.
+func repositoryError(err, fallback error) error {
+	var domainErr *entities.DomainError
+	if errors.As(err, &domainErr) {
+		return err
+	}
+	return fallback
+}
@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"strconv"
+)
+
+type ExportGroupsUsecase struct {
+	groupRepo   ExportGroupsRepository
+	teacherRepo ExportTeachersRepository
+}
+
+type ExportGroupsRequestDto struct {
+	ExportRequest
+}
+
+func NewExportGroupsUsecase(groupRepo ExportGroupsRepository, teacherRepo ExportTeachersRepository) ExportGroupsUsecase {
+	return ExportGroupsUsecase{groupRepo: groupRepo, teacherRepo: teacherRepo}
+}
+
+func (uc *ExportGroupsUsecase) ExportGroups(ctx context.Context, request ExportGroupsRequestDto) (Export, error) {
+	query, err := exportQuery(request.ExportRequest, groupSortKeys, "teacher_id")
+	if err != nil {
+		return Export{}, err
+	}
+
+	teacherNames := make(map[int]string)
+	columns, err := groupExportColumns(teacherNames).pick(request.Columns)
+	if err != nil {
+		return Export{}, err
+	}
+	if columns.has("teacher") {
+		err = uc.teacherRepo.Each(ctx, entities.ListQuery{Sort: "id"}, func(teacher entities.Teacher) error {
+			teacherNames[teacher.Id] = teacher.Fio
+			return nil
+		})
+		if err != nil {
+			return Export{}, ReadError
+		}
+	}
+
+	return columns.export("Groups", func(ctx context.Context, fn func(entities.Group) error) error {
+		return uc.groupRepo.Each(ctx, query, fn)
+	}), nil
+}
+
+// groupExportColumns are the columns of a group export; teacher is the fio of
+// the teacher, looked up in teacherNames.
+func groupExportColumns(teacherNames map[int]string) exportColumns[entities.Group] {
+	return exportColumns[entities.Group]{
+		{"id", func(g entities.Group) string { return strconv.Itoa(g.Id) }},
+		{"name", func(g entities.Group) string { return g.Name }},
+		{"teacher_id", func(g entities.Group) string { return optionalId(g.TeacherId) }},
+		{"teacher", func(g entities.Group) string { return teacherNames[g.TeacherId] }},
+	}
+}
@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+	"strconv"
+)
+
+type ExportStudentsUsecase struct {
+	studentRepo ExportStudentsRepository
+	groupRepo   ExportGroupsRepository
+}
+
+// ExportStudentsRequestDto selects all the students or, with GroupId, the
+// roster of a group.
+type ExportStudentsRequestDto struct {
+	GroupId int
+	ExportRequest
+}
+
+func NewExportStudentsUsecase(studentRepo ExportStudentsRepository, groupRepo ExportGroupsRepository) ExportStudentsUsecase {
+	return ExportStudentsUsecase{studentRepo: studentRepo, groupRepo: groupRepo}
+}
+
+func (uc *ExportStudentsUsecase) ExportStudents(ctx context.Context, request ExportStudentsRequestDto) (Export, error) {
+	title := "Students"
+	filters := []string{"fio", "group_id", "teacher_id", "no_group"}
+	if request.GroupId != 0 {
+		group, err := uc.groupRepo.ReadById(ctx, request.GroupId)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return Export{}, NotFoundError
+		}
+		if err != nil {
+			return Export{}, ReadError
+		}
+		title = group.Name
+		filters = []string{"fio"}
+	}
+
+	query, err := exportQuery(request.ExportRequest, studentSortKeys, filters...)
+	if err != nil {
+		return Export{}, err
+	}
+	if request.GroupId != 0 {
+		query.Filter.GroupId = request.GroupId
+	}
+
+	groupNames := make(map[int]string)
+	columns, err := studentExportColumns(groupNames).pick(request.Columns)
+	if err != nil {
+		return Export{}, err
+	}
+	if columns.has("group") {
+		err = uc.groupRepo.Each(ctx, entities.ListQuery{Sort: "id"}, func(group entities.Group) error {
+			groupNames[group.Id] = group.Name
+			return nil
+		})
+		if err != nil {
+			return Export{}, ReadError
+		}
+	}
+
+	return columns.export(title, func(ctx context.Context, fn func(entities.Student) error) error {
+		return uc.studentRepo.Each(ctx, query, fn)
+	}), nil
+}
+
+// studentExportColumns are the columns of a student export; group is the name
+// of the group, looked up in groupNames.
+func studentExportColumns(groupNames map[int]string) exportColumns[entities.Student] {
+	return exportColumns[entities.Student]{
+		{"id", func(s entities.Student) string { return strconv.Itoa(s.Id) }},
+		{"fio", func(s entities.Student) string { return s.Fio }},
+		{"phone_number", func(s entities.Student) string { return s.PhoneNumber }},
+		{"group_id", func(s entities.Student) string { return optionalId(s.GroupId) }},
+		{"group", func(s entities.Student) string { return groupNames[s.GroupId] }},
+	}
+}
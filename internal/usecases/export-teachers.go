@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"strconv"
+)
+
+var teacherExportColumns = exportColumns[entities.Teacher]{
+	{"id", func(t entities.Teacher) string { return strconv.Itoa(t.Id) }},
+	{"fio", func(t entities.Teacher) string { return t.Fio }},
+	{"phone_number", func(t entities.Teacher) string { return t.PhoneNumber }},
+}
+
+type ExportTeachersUsecase struct {
+	teacherRepo ExportTeachersRepository
+}
+
+type ExportTeachersRequestDto struct {
+	ExportRequest
+}
+
+func NewExportTeachersUsecase(teacherRepo ExportTeachersRepository) ExportTeachersUsecase {
+	return ExportTeachersUsecase{teacherRepo: teacherRepo}
+}
+
+func (uc *ExportTeachersUsecase) ExportTeachers(ctx context.Context, request ExportTeachersRequestDto) (Export, error) {
+	query, err := exportQuery(request.ExportRequest, teacherSortKeys, "fio", "no_group")
+	if err != nil {
+		return Export{}, err
+	}
+
+	columns, err := teacherExportColumns.pick(request.Columns)
+	if err != nil {
+		return Export{}, err
+	}
+
+	return columns.export("Teachers", func(ctx context.Context, fn func(entities.Teacher) error) error {
+		return uc.teacherRepo.Each(ctx, query, fn)
+	}), nil
+}
@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// ExportRequest selects the records of a list to export, their order and the
+// columns to write. No columns means all of them.
+type ExportRequest struct {
+	Sort    string
+	Desc    bool
+	Filter  entities.ListFilter
+	Columns []string
+}
+
+// Export is a table ready to be written. Rows streams it to fn, the header
+// first, as the records are read.
+type Export struct {
+	Title string
+	Rows  func(ctx context.Context, fn func(row []string) error) error
+}
+
+// exportColumn is a column an export may have. Its name heads the column and
+// selects it in ExportRequest.Columns.
+type exportColumn[T any] struct {
+	name  string
+	value func(T) string
+}
+
+type exportColumns[T any] []exportColumn[T]
+
+// pick returns the named columns in the order given, or all of them.
+func (columns exportColumns[T]) pick(names []string) (exportColumns[T], error) {
+	if len(names) == 0 {
+		return columns, nil
+	}
+
+	picked := make(exportColumns[T], 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(columns, func(column exportColumn[T]) bool { return column.name == name })
+		if i < 0 {
+			return nil, ValidationError.WithFields(entities.FieldError{Field: "columns", Message: fmt.Sprintf("unknown column %q", name)})
+		}
+		picked = append(picked, columns[i])
+	}
+	return picked, nil
+}
+
+func (columns exportColumns[T]) has(name string) bool {
+	return slices.ContainsFunc(columns, func(column exportColumn[T]) bool { return column.name == name })
+}
+
+// export streams the records each reads as rows of the columns. Errors of
+// fn are returned as they are, failures to read as ReadError.
+func (columns exportColumns[T]) export(title string, each func(ctx context.Context, fn func(T) error) error) Export {
+	return Export{
+		Title: title,
+		Rows: func(ctx context.Context, fn func(row []string) error) error {
+			header := make([]string, len(columns))
+			for i, column := range columns {
+				header[i] = column.name
+			}
+			if err := fn(header); err != nil {
+				return err
+			}
+
+			var writeErr error
+			err := each(ctx, func(record T) error {
+				row := make([]string, len(columns))
+				for i, column := range columns {
+					row[i] = column.value(record)
+				}
+				writeErr = fn(row)
+				return writeErr
+			})
+			if writeErr != nil {
+				return writeErr
+			}
+			if err != nil {
+				return ReadError
+			}
+			return nil
+		},
+	}
+}
+
+// exportQuery checks the order and the filters of an export like those of a
+// list. An export has no pages: it holds every record.
+func exportQuery[T any](request ExportRequest, keys sortKeys[T], filters ...string) (entities.ListQuery, error) {
+	list, err := newListing(ListRequest{Sort: request.Sort, Desc: request.Desc, Filter: request.Filter}, keys, filters...)
+	if err != nil {
+		return entities.ListQuery{}, err
+	}
+
+	query := list.query
+	query.Limit = 0
+	return query, nil
+}
+
+// optionalId formats an optional reference, zero meaning none.
+func optionalId(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
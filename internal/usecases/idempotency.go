@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Idempotency lets clients retry a request safely: the first request sent
+// with a key is executed and its response stored, retries with the same key
+// and the same request get the stored response. Keys are scoped to the user
+// and kept for ttl. A request that neither finished nor was released within
+// lockTimeout, e.g. because the instance serving it died, gives its key up.
+type Idempotency struct {
+	repo        IdempotencyKeyRepository
+	ttl         time.Duration
+	lockTimeout time.Duration
+}
+
+func NewIdempotency(repo IdempotencyKeyRepository, ttl, lockTimeout time.Duration) *Idempotency {
+	return &Idempotency{repo: repo, ttl: ttl, lockTimeout: lockTimeout}
+}
+
+// Begin reserves the key for the request with the fingerprint. If the key was
+// already used for the same request and that one is done, its record is
+// returned with true and the response should be replayed instead of running
+// the request again.
+func (i *Idempotency) Begin(ctx context.Context, userId int, key, fingerprint string) (entities.IdempotencyKey, bool, error) {
+	now := time.Now()
+	record := entities.IdempotencyKey{UserId: userId, Key: key, Fingerprint: fingerprint, CreatedAt: now, ExpiresAt: now.Add(i.ttl)}
+
+	reserved, err := i.repo.Reserve(ctx, record, now.Add(-i.lockTimeout))
+	if err != nil {
+		return entities.IdempotencyKey{}, false, CreateError
+	}
+	if reserved {
+		return record, false, nil
+	}
+
+	stored, err := i.repo.Read(ctx, userId, key)
+	if errors.Is(err, entities.RecordNotFoundError) {
+		// purged in between; the next attempt reserves it
+		return entities.IdempotencyKey{}, false, RequestInProgressError
+	}
+	if err != nil {
+		return entities.IdempotencyKey{}, false, ReadError
+	}
+
+	switch {
+	case stored.Fingerprint != fingerprint:
+		return entities.IdempotencyKey{}, false, IdempotencyKeyReuseError
+	case !stored.IsDone():
+		return entities.IdempotencyKey{}, false, RequestInProgressError
+	}
+	return stored, true, nil
+}
+
+// Complete stores the response to the request record was reserved for.
+func (i *Idempotency) Complete(ctx context.Context, record entities.IdempotencyKey) error {
+	record.ExpiresAt = time.Now().Add(i.ttl)
+
+	err := i.repo.Complete(ctx, record)
+	if err != nil {
+		return UpdateError
+	}
+	return nil
+}
+
+// Release frees the key of a request that failed in a way worth retrying.
+func (i *Idempotency) Release(ctx context.Context, userId int, key string) error {
+	err := i.repo.Delete(ctx, userId, key)
+	if err != nil {
+		return DeleteError
+	}
+	return nil
+}
+
+func (i *Idempotency) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := i.repo.DeleteExpired(ctx); err != nil {
+				fmt.Println("failed to delete expired idempotency keys:", err)
+			}
+		}
+	}
+}
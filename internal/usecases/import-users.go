@@ -0,0 +1,208 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const maxImportRows = 5000
+
+// errDryRun rolls the transaction of a dry run back once every row went in.
+var errDryRun = errors.New("dry run")
+
+type ImportUsersUsecase struct {
+	userRepo  CreateUserRepository
+	groupRepo GroupNamesRepository
+	crypto    Cryptographer
+	generator PasswordGenerator
+	tx        Transactor
+	audit     AuditRecorder
+}
+
+// ImportUserRow is a user to create; Row is its line in the uploaded file.
+type ImportUserRow struct {
+	Row         int
+	Login       string
+	Fio         string
+	PhoneNumber string
+	Role        string
+	GroupName   string
+}
+
+type ImportUsersRequestDto struct {
+	Rows   []ImportUserRow
+	DryRun bool
+}
+
+// ImportedUser is a created account. Id and TemporaryPassword are empty in a
+// dry run.
+type ImportedUser struct {
+	Row               int    `json:"row"`
+	Id                int    `json:"id,omitempty"`
+	Login             string `json:"login"`
+	Role              string `json:"role"`
+	Fio               string `json:"fio,omitempty"`
+	GroupId           int    `json:"group_id,omitempty"`
+	TemporaryPassword string `json:"temporary_password,omitempty"`
+}
+
+type ImportUsersResponseDto struct {
+	DryRun bool           `json:"dry_run"`
+	Users  []ImportedUser `json:"users"`
+}
+
+func NewImportUsersUsecase(userRepo CreateUserRepository, groupRepo GroupNamesRepository, crypto Cryptographer, generator PasswordGenerator, tx Transactor, audit AuditRecorder) ImportUsersUsecase {
+	return ImportUsersUsecase{userRepo: userRepo, groupRepo: groupRepo, crypto: crypto, generator: generator, tx: tx, audit: audit}
+}
+
+// ImportUsers creates student and teacher accounts with generated one-time
+// passwords, all of them or none: if any row is invalid nothing is created
+// and the errors of every row are returned. A dry run checks the rows the
+// same way, inserts included, and rolls back.
+func (uc *ImportUsersUsecase) ImportUsers(ctx context.Context, request ImportUsersRequestDto) (ImportUsersResponseDto, error) {
+	response := ImportUsersResponseDto{DryRun: request.DryRun}
+
+	if len(request.Rows) == 0 {
+		return response, ValidationError.WithFields(entities.FieldError{Field: "file", Message: "has no rows"})
+	}
+	if len(request.Rows) > maxImportRows {
+		return response, ValidationError.WithFields(entities.FieldError{Field: "file", Message: "has too many rows"})
+	}
+
+	groupIds, fieldErrors, err := uc.checkRows(ctx, request.Rows)
+	if err != nil {
+		return response, err
+	}
+	if len(fieldErrors) > 0 {
+		return response, ValidationError.WithFields(fieldErrors...)
+	}
+
+	// hashing is slow and memory hungry, so it is done before the
+	// transaction takes a connection and holds locks
+	credentials := make([]struct{ password, hash, salt string }, len(request.Rows))
+	if !request.DryRun {
+		for i := range credentials {
+			credential := &credentials[i]
+			credential.password, err = uc.generator.GenerateTemporaryPassword()
+			if err != nil {
+				return response, HashPasswordError
+			}
+			credential.hash, credential.salt, err = uc.crypto.HashPassword(credential.password)
+			if err != nil {
+				return response, HashPasswordError
+			}
+		}
+	}
+
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		users := make([]ImportedUser, 0, len(request.Rows))
+		for i, row := range request.Rows {
+			user := entities.User{Login: row.Login, Role: row.Role, Password: credentials[i].hash, Salt: credentials[i].salt, MustChangePassword: true}
+			profile := entities.Profile{Fio: row.Fio, PhoneNumber: row.PhoneNumber, GroupId: groupIds[row.GroupName]}
+
+			id, err := uc.userRepo.Create(ctx, user, profile)
+			if err != nil {
+				fieldErr, ok := importRowError(row, err)
+				if !ok {
+					return CreateError
+				}
+				fieldErrors = append(fieldErrors, fieldErr)
+				continue
+			}
+
+			imported := ImportedUser{Row: row.Row, Login: row.Login, Role: row.Role, Fio: row.Fio, GroupId: profile.GroupId}
+			if !request.DryRun {
+				user.Id = id
+				err = uc.audit.Record(ctx, entities.AuditCreate, entities.AuditEntityUser, id, nil, map[string]any{"user": user, "profile": profile, "source": "import"})
+				if err != nil {
+					return err
+				}
+				imported.Id = id
+				imported.TemporaryPassword = credentials[i].password
+			}
+			users = append(users, imported)
+		}
+
+		if len(fieldErrors) > 0 {
+			return ValidationError.WithFields(fieldErrors...)
+		}
+		response.Users = users
+		if request.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return ImportUsersResponseDto{DryRun: request.DryRun}, err
+	}
+
+	return response, nil
+}
+
+// checkRows validates what can be told without inserting: the roles, logins
+// repeated in the file and the groups. It returns the ids of the groups by
+// name.
+func (uc *ImportUsersUsecase) checkRows(ctx context.Context, rows []ImportUserRow) (map[string]int, []entities.FieldError, error) {
+	var fieldErrors []entities.FieldError
+	logins := make(map[string]int, len(rows))
+	var names []string
+	for _, row := range rows {
+		login := strings.ToLower(row.Login)
+		if first, ok := logins[login]; ok {
+			fieldErrors = append(fieldErrors, entities.FieldError{Row: row.Row, Field: "login", Message: "repeats the login of row " + strconv.Itoa(first)})
+		} else {
+			logins[login] = row.Row
+		}
+
+		if row.Role != "student" && row.Role != "teacher" {
+			fieldErrors = append(fieldErrors, entities.FieldError{Row: row.Row, Field: "role", Message: "must be student or teacher"})
+		}
+		if row.GroupName != "" {
+			if row.Role != "student" {
+				fieldErrors = append(fieldErrors, entities.FieldError{Row: row.Row, Field: "group", Message: "is accepted for students only"})
+			}
+			names = append(names, row.GroupName)
+		}
+	}
+
+	groupIds := make(map[string]int)
+	if len(names) == 0 {
+		return groupIds, fieldErrors, nil
+	}
+
+	found, err := uc.groupRepo.ReadIdsByNames(ctx, names)
+	if err != nil {
+		return nil, nil, ReadError
+	}
+	for _, row := range rows {
+		if row.GroupName == "" {
+			continue
+		}
+		switch ids := found[row.GroupName]; len(ids) {
+		case 0:
+			fieldErrors = append(fieldErrors, entities.FieldError{Row: row.Row, Field: "group", Message: "no such group"})
+		case 1:
+			groupIds[row.GroupName] = ids[0]
+		default:
+			fieldErrors = append(fieldErrors, entities.FieldError{Row: row.Row, Field: "group", Message: "several groups have this name"})
+		}
+	}
+
+	return groupIds, fieldErrors, nil
+}
+
+// importRowError describes a row the repository refused to insert.
+func importRowError(row ImportUserRow, err error) (entities.FieldError, bool) {
+	switch {
+	case errors.Is(err, entities.DuplicateLoginError):
+		return entities.FieldError{Row: row.Row, Field: "login", Message: "is already taken"}, true
+	case errors.Is(err, entities.UnknownGroupError):
+		return entities.FieldError{Row: row.Row, Field: "group", Message: "no such group"}, true
+	case errors.Is(err, entities.InvalidRoleError):
+		return entities.FieldError{Row: row.Row, Field: "role", Message: "no such role"}, true
+	}
+	return entities.FieldError{}, false
+}
@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+// ListRequest holds the paging, sorting and filtering parameters of a list.
+// Cursor is the next_cursor of the previous page, empty for the first one.
+// Unpaged returns every record in a single page, as the lists did before
+// they were paged.
+type ListRequest struct {
+	Sort      string
+	Desc      bool
+	Limit     int
+	Cursor    string
+	WithTotal bool
+	Unpaged   bool
+	Filter    entities.ListFilter
+}
+
+// sortKeys whitelists the fields a list can be sorted by and reads their
+// values, an int or a string, from a record. Every list has an "id" key.
+type sortKeys[T any] map[string]func(T) any
+
+// listing is a validated ListRequest for records of type T.
+type listing[T any] struct {
+	query entities.ListQuery
+	limit int
+	keys  sortKeys[T]
+}
+
+// cursor is what next_cursor encodes. It remembers the order it was made for
+// so that it can't be used with another one.
+type cursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d,omitempty"`
+	Value any    `json:"v"`
+	Id    int    `json:"id"`
+}
+
+// newListing checks request against the sort fields in keys and the names of
+// the filters the list supports.
+func newListing[T any](request ListRequest, keys sortKeys[T], filters ...string) (listing[T], error) {
+	sort := request.Sort
+	if sort == "" {
+		sort = "id"
+	}
+	key, ok := keys[sort]
+	if !ok {
+		return listing[T]{}, ValidationError.WithFields(entities.FieldError{Field: "sort", Message: fmt.Sprintf("cannot sort by %q", sort)})
+	}
+
+	for _, name := range filterNames(request.Filter) {
+		if !slices.Contains(filters, name) {
+			return listing[T]{}, ValidationError.WithFields(entities.FieldError{Field: name, Message: "is not supported by this list"})
+		}
+	}
+
+	limit := request.Limit
+	if limit < 0 {
+		return listing[T]{}, ValidationError.WithFields(entities.FieldError{Field: "limit", Message: "must not be negative"})
+	}
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	l := listing[T]{
+		// one record more than a page tells whether there is a next page
+		query: entities.ListQuery{Filter: request.Filter, Sort: sort, Desc: request.Desc, Limit: limit + 1},
+		limit: limit,
+		keys:  keys,
+	}
+	if request.Unpaged {
+		l.query.Limit, l.limit = 0, 0
+	}
+
+	if request.Cursor != "" {
+		var zero T
+		after, err := decodeCursor(request.Cursor, sort, request.Desc, key(zero))
+		if err != nil {
+			return listing[T]{}, err
+		}
+		l.query.After = &after
+	}
+
+	return l, nil
+}
+
+// page cuts the records read with the listing's query down to a page and
+// returns the cursor of the next page, empty on the last one.
+func (l listing[T]) page(records []T) ([]T, string) {
+	if l.limit == 0 || len(records) <= l.limit {
+		return records, ""
+	}
+
+	records = records[:l.limit]
+	last := records[l.limit-1]
+	id, _ := l.keys["id"](last).(int)
+	next := cursor{Sort: l.query.Sort, Desc: l.query.Desc, Value: l.keys[l.query.Sort](last), Id: id}
+
+	raw, err := json.Marshal(next)
+	if err != nil {
+		return records, ""
+	}
+	return records, base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor reads a next_cursor made for the same order whose value has
+// the type of sample.
+func decodeCursor(encoded, sort string, desc bool, sample any) (entities.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return entities.Cursor{}, InvalidCursorError
+	}
+
+	var c cursor
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err = decoder.Decode(&c); err != nil || c.Sort != sort || c.Desc != desc {
+		return entities.Cursor{}, InvalidCursorError
+	}
+
+	switch sample.(type) {
+	case int:
+		number, ok := c.Value.(json.Number)
+		if !ok {
+			return entities.Cursor{}, InvalidCursorError
+		}
+		value, err := number.Int64()
+		if err != nil {
+			return entities.Cursor{}, InvalidCursorError
+		}
+		return entities.Cursor{Value: int(value), Id: c.Id}, nil
+	case string:
+		value, ok := c.Value.(string)
+		if !ok {
+			return entities.Cursor{}, InvalidCursorError
+		}
+		return entities.Cursor{Value: value, Id: c.Id}, nil
+	}
+	return entities.Cursor{}, InvalidCursorError
+}
+
+func filterNames(filter entities.ListFilter) []string {
+	var names []string
+	if filter.Fio != "" {
+		names = append(names, "fio")
+	}
+	if filter.GroupId != 0 {
+		names = append(names, "group_id")
+	}
+	if filter.TeacherId != 0 {
+		names = append(names, "teacher_id")
+	}
+	if filter.NoGroup {
+		names = append(names, "no_group")
+	}
+	return names
+}
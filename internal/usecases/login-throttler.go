@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	loginAttemptKeyPrefix = "login:"
+	ipAttemptKeyPrefix    = "ip:"
+)
+
+// LoginThrottlerConfig configures a LoginThrottler. Scope prefixes its keys,
+// so that a throttler guarding another endpoint keeps separate counters; it
+// is empty for authentication.
+type LoginThrottlerConfig struct {
+	Scope            string
+	MaxLoginFailures int
+	MaxIpFailures    int
+	FailureWindow    time.Duration
+	LockoutDuration  time.Duration
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+}
+
+// ThrottledError is returned while a login or an address is locked out or
+// has to wait before the next attempt.
+type ThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+// RetryAfterSeconds is RetryAfter rounded up, as used in the Retry-After
+// header.
+func (e *ThrottledError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+func (e *ThrottledError) ErrorCode() entities.ErrorCode {
+	return entities.RateLimitedCode
+}
+
+func (e *ThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("too many failed attempts, locked for %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("too many failed attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// LoginThrottler counts failed authentications per login and per client
+// address. Every failure doubles the delay before the next attempt is
+// accepted (up to MaxDelay), and after the configured number of failures the
+// key is locked out for LockoutDuration.
+type LoginThrottler struct {
+	repo LoginAttemptRepository
+	cfg  LoginThrottlerConfig
+}
+
+func NewLoginThrottler(repo LoginAttemptRepository, cfg LoginThrottlerConfig) *LoginThrottler {
+	return &LoginThrottler{repo: repo, cfg: cfg}
+}
+
+func LoginAttemptKey(login string) string {
+	return loginAttemptKeyPrefix + strings.ToLower(login)
+}
+
+func IpAttemptKey(ip string) string {
+	return ipAttemptKeyPrefix + ip
+}
+
+func (t *LoginThrottler) Check(ctx context.Context, login, ip string) error {
+	for _, key := range t.keys(login, ip) {
+		attempt, err := t.repo.Read(ctx, key)
+		if err != nil {
+			return ReadError
+		}
+
+		if err = t.checkAttempt(attempt, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *LoginThrottler) RegisterFailure(ctx context.Context, login, ip string) error {
+	for _, key := range t.keys(login, ip) {
+		attempt, err := t.repo.RegisterFailure(ctx, key, t.cfg.FailureWindow)
+		if err != nil {
+			return UpdateError
+		}
+
+		limit := t.cfg.MaxLoginFailures
+		if key == t.cfg.Scope+IpAttemptKey(ip) {
+			limit = t.cfg.MaxIpFailures
+		}
+
+		if limit > 0 && attempt.Failures >= limit {
+			err = t.repo.Lock(ctx, key, time.Now().Add(t.cfg.LockoutDuration))
+			if err != nil {
+				return UpdateError
+			}
+		}
+	}
+
+	return nil
+}
+
+// RegisterSuccess forgets the failures of the login. The address counter is
+// kept: a successful login from a shared school network must not reset the
+// counter for somebody guessing other accounts from the same address.
+func (t *LoginThrottler) RegisterSuccess(ctx context.Context, login string) error {
+	if login == "" {
+		return nil
+	}
+
+	err := t.repo.Reset(ctx, t.cfg.Scope+LoginAttemptKey(login))
+	if err != nil {
+		return DeleteError
+	}
+
+	return nil
+}
+
+func (t *LoginThrottler) checkAttempt(attempt entities.LoginAttempt, now time.Time) error {
+	if attempt.IsLocked(now) {
+		return &ThrottledError{RetryAfter: attempt.LockedUntil.Sub(now), Locked: true}
+	}
+
+	if attempt.Failures == 0 || attempt.LastFailure.Before(now.Add(-t.cfg.FailureWindow)) {
+		return nil
+	}
+
+	if nextAttempt := attempt.LastFailure.Add(t.delay(attempt.Failures)); now.Before(nextAttempt) {
+		return &ThrottledError{RetryAfter: nextAttempt.Sub(now)}
+	}
+
+	return nil
+}
+
+func (t *LoginThrottler) delay(failures int) time.Duration {
+	delay := t.cfg.BaseDelay
+	for i := 1; i < failures && delay < t.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > t.cfg.MaxDelay {
+		return t.cfg.MaxDelay
+	}
+	return delay
+}
+
+func (t *LoginThrottler) keys(login, ip string) []string {
+	var keys []string
+	if login != "" {
+		keys = append(keys, t.cfg.Scope+LoginAttemptKey(login))
+	}
+	if ip != "" {
+		keys = append(keys, t.cfg.Scope+IpAttemptKey(ip))
+	}
+	return keys
+}
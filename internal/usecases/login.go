@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+)
+
+type LoginUsecase struct {
+	auth      Authenticator
+	tokenRepo CreateRefreshTokenRepository
+	jwt       JWTGenerator
+}
+
+type LoginRequestDto struct {
+	Login    string
+	Password string
+	Ip       string
+}
+
+type LoginResponseDto struct {
+	Id           int    `json:"id"`
+	Role         string `json:"role"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	Profile      any    `json:"profile"`
+}
+
+func NewLoginUsecase(auth Authenticator, tokenRepo CreateRefreshTokenRepository, jwt JWTGenerator) LoginUsecase {
+	return LoginUsecase{auth: auth, tokenRepo: tokenRepo, jwt: jwt}
+}
+
+func (uc *LoginUsecase) Login(ctx context.Context, request LoginRequestDto) (LoginResponseDto, error) {
+	var response LoginResponseDto
+
+	// Unknown login and wrong password are reported the same way so that
+	// the endpoint can't be used to enumerate existing accounts.
+	user, err := uc.auth.Authenticate(ctx, request.Login, request.Password, request.Ip)
+	if err != nil {
+		var throttled *ThrottledError
+		if errors.As(err, &throttled) {
+			return response, err
+		}
+		return response, InvalidCredentialsError
+	}
+
+	profile, err := uc.auth.GetProfile(ctx, user)
+	if err != nil {
+		return response, UserAccountNotFoundError
+	}
+
+	tokens, refreshToken, err := generateTokenPair(uc.jwt, user, "")
+	if err != nil {
+		return response, err
+	}
+
+	err = uc.tokenRepo.Create(ctx, refreshToken)
+	if err != nil {
+		return response, CreateError
+	}
+
+	response = LoginResponseDto{
+		Id:           user.Id,
+		Role:         user.Role,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		Profile:      profile,
+	}
+	return response, nil
+}
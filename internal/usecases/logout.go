@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"time"
+)
+
+type LogoutUsecase struct {
+	jwt       JWTGenerator
+	denylist  AccessTokenDenylist
+	tokenRepo RevokeRefreshTokensRepository
+}
+
+type LogoutRequestDto struct {
+	AccessToken string
+}
+
+func NewLogoutUsecase(jwt JWTGenerator, denylist AccessTokenDenylist, tokenRepo RevokeRefreshTokensRepository) LogoutUsecase {
+	return LogoutUsecase{jwt: jwt, denylist: denylist, tokenRepo: tokenRepo}
+}
+
+func (uc *LogoutUsecase) Logout(ctx context.Context, request LogoutRequestDto) error {
+	claims, err := uc.jwt.ParseAccessJWT(request.AccessToken)
+	if err != nil {
+		return InvalidTokenError
+	}
+
+	jti, _ := claims["jti"].(string)
+	sub, _ := claims["sub"].(float64)
+	exp, _ := claims["exp"].(float64)
+	if jti == "" {
+		return InvalidTokenError
+	}
+
+	err = uc.denylist.Revoke(ctx, entities.RevokedToken{Id: jti, UserId: int(sub), ExpiresAt: time.Unix(int64(exp), 0)})
+	if err != nil {
+		return err
+	}
+
+	if sid, _ := claims["sid"].(string); sid != "" {
+		err = uc.tokenRepo.RevokeFamily(ctx, sid)
+		if err != nil {
+			return UpdateError
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+)
+
+type PurgeDeletedUsecase struct {
+	trashRepo TrashRepository
+	tx        Transactor
+	audit     AuditRecorder
+}
+
+type PurgeDeletedRequestDto struct {
+	EntityType string
+	Id         int
+}
+
+func NewPurgeDeletedUsecase(trashRepo TrashRepository, tx Transactor, audit AuditRecorder) PurgeDeletedUsecase {
+	return PurgeDeletedUsecase{trashRepo: trashRepo, tx: tx, audit: audit}
+}
+
+// PurgeDeleted permanently removes a record that has been soft-deleted before.
+// Records that are not deleted are left alone.
+func (uc *PurgeDeletedUsecase) PurgeDeleted(ctx context.Context, request PurgeDeletedRequestDto) error {
+	if !trashEntityTypes[request.EntityType] {
+		return UnknownEntityTypeError
+	}
+
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		record, err := uc.trashRepo.ReadDeletedById(ctx, request.EntityType, request.Id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return RecordNotFoundError
+		}
+		if err != nil {
+			return ReadError
+		}
+
+		err = uc.trashRepo.Purge(ctx, request.EntityType, request.Id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return RecordNotFoundError
+		}
+		if err != nil {
+			return repositoryError(err, DeleteError)
+		}
+
+		return uc.audit.Record(ctx, entities.AuditPurge, request.EntityType, request.Id, record, nil)
+	})
+}
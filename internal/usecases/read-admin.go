@@ -3,6 +3,7 @@ package usecases
 import (
 	"backendForKeenEye/internal/entities"
 	"context"
+	"errors"
 )
 
 type ReadAdminUsecase struct {
@@ -10,7 +11,8 @@ type ReadAdminUsecase struct {
 }
 
 type ReadAdminRequestDto struct {
-	Id int
+	Id             int
+	IncludeDeleted bool
 }
 
 type ReadAdminResponseDto struct {
@@ -24,7 +26,15 @@ func NewReadAdminUsecase(AdminRepo ReadAdminRepository) ReadAdminUsecase {
 func (uc *ReadAdminUsecase) ReadAdmin(ctx context.Context, request ReadAdminRequestDto) (ReadAdminResponseDto, error) {
 	var response ReadAdminResponseDto
 
-	admin, err := uc.AdminRepo.ReadById(ctx, request.Id)
+	read := uc.AdminRepo.ReadById
+	if request.IncludeDeleted {
+		read = uc.AdminRepo.ReadByIdIncludingDeleted
+	}
+
+	admin, err := read(ctx, request.Id)
+	if errors.Is(err, entities.RecordNotFoundError) {
+		return response, NotFoundError
+	}
 	if err != nil {
 		return response, ReadError
 	}
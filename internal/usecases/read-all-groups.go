@@ -5,28 +5,52 @@ import (
 	"context"
 )
 
+var groupSortKeys = sortKeys[entities.Group]{
+	"id":         func(g entities.Group) any { return g.Id },
+	"name":       func(g entities.Group) any { return g.Name },
+	"teacher_id": func(g entities.Group) any { return g.TeacherId },
+}
+
 type ReadAllGroupsUsecase struct {
 	GroupRepo ReadAllGroupsRepository
 }
 
+type ReadAllGroupsRequestDto struct {
+	ListRequest
+}
+
 type ReadAllGroupsResponseDto struct {
-	Groups []entities.Group `json:"groups"`
+	Groups     []entities.Group `json:"groups"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+	Total      *int             `json:"total,omitempty"`
 }
 
 func NewReadAllGroupsUsecase(GroupRepo ReadAllGroupsRepository) ReadAllGroupsUsecase {
 	return ReadAllGroupsUsecase{GroupRepo: GroupRepo}
 }
 
-func (uc *ReadAllGroupsUsecase) ReadAllGroups(ctx context.Context) (ReadAllGroupsResponseDto, error) {
+func (uc *ReadAllGroupsUsecase) ReadAllGroups(ctx context.Context, request ReadAllGroupsRequestDto) (ReadAllGroupsResponseDto, error) {
 	var response ReadAllGroupsResponseDto
 
-	groups, err := uc.GroupRepo.Read(ctx)
+	list, err := newListing(request.ListRequest, groupSortKeys, "teacher_id")
+	if err != nil {
+		return response, err
+	}
+
+	groups, err := uc.GroupRepo.Read(ctx, list.query)
 	if err != nil {
 		return response, ReadError
 	}
 
-	response = ReadAllGroupsResponseDto{
-		Groups: groups,
+	response.Groups, response.NextCursor = list.page(groups)
+
+	if request.WithTotal {
+		total, err := uc.GroupRepo.Count(ctx, request.Filter)
+		if err != nil {
+			return response, ReadError
+		}
+		response.Total = &total
 	}
+
 	return response, nil
 }
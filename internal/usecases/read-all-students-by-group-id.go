@@ -10,11 +10,14 @@ type ReadAllStudentsByGroupIdUsecase struct {
 }
 
 type ReadAllStudentsByGroupIdResponseDto struct {
-	Students []entities.Student `json:"students"`
+	Students   []entities.Student `json:"students"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	Total      *int               `json:"total,omitempty"`
 }
 
 type ReadAllStudentsByGroupIdRequestDto struct {
 	GroupId int
+	ListRequest
 }
 
 func NewReadAllStudentsByGroupIdUsecase(studentRepo ReadAllStudentsByGroupIdRepository) ReadAllStudentsByGroupIdUsecase {
@@ -24,13 +27,26 @@ func NewReadAllStudentsByGroupIdUsecase(studentRepo ReadAllStudentsByGroupIdRepo
 func (uc *ReadAllStudentsByGroupIdUsecase) ReadAllStudentsByGroupId(ctx context.Context, request ReadAllStudentsByGroupIdRequestDto) (ReadAllStudentsByGroupIdResponseDto, error) {
 	var response ReadAllStudentsByGroupIdResponseDto
 
-	students, err := uc.StudentRepo.ReadByGroupId(ctx, request.GroupId)
+	list, err := newListing(request.ListRequest, studentSortKeys, "fio")
+	if err != nil {
+		return response, err
+	}
+	list.query.Filter.GroupId = request.GroupId
+
+	students, err := uc.StudentRepo.Read(ctx, list.query)
 	if err != nil {
 		return response, ReadError
 	}
 
-	response = ReadAllStudentsByGroupIdResponseDto{
-		Students: students,
+	response.Students, response.NextCursor = list.page(students)
+
+	if request.WithTotal {
+		total, err := uc.StudentRepo.Count(ctx, list.query.Filter)
+		if err != nil {
+			return response, ReadError
+		}
+		response.Total = &total
 	}
+
 	return response, nil
 }
@@ -5,28 +5,52 @@ import (
 	"context"
 )
 
+var studentSortKeys = sortKeys[entities.Student]{
+	"id":       func(s entities.Student) any { return s.Id },
+	"fio":      func(s entities.Student) any { return s.Fio },
+	"group_id": func(s entities.Student) any { return s.GroupId },
+}
+
 type ReadAllStudentsUsecase struct {
 	StudentRepo ReadAllStudentsRepository
 }
 
+type ReadAllStudentsRequestDto struct {
+	ListRequest
+}
+
 type ReadAllStudentsResponseDto struct {
-	Students []entities.Student `json:"students"`
+	Students   []entities.Student `json:"students"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	Total      *int               `json:"total,omitempty"`
 }
 
 func NewReadAllStudentsUsecase(StudentRepo ReadAllStudentsRepository) ReadAllStudentsUsecase {
 	return ReadAllStudentsUsecase{StudentRepo: StudentRepo}
 }
 
-func (uc *ReadAllStudentsUsecase) ReadAllStudents(ctx context.Context) (ReadAllStudentsResponseDto, error) {
+func (uc *ReadAllStudentsUsecase) ReadAllStudents(ctx context.Context, request ReadAllStudentsRequestDto) (ReadAllStudentsResponseDto, error) {
 	var response ReadAllStudentsResponseDto
 
-	students, err := uc.StudentRepo.Read(ctx)
+	list, err := newListing(request.ListRequest, studentSortKeys, "fio", "group_id", "teacher_id", "no_group")
+	if err != nil {
+		return response, err
+	}
+
+	students, err := uc.StudentRepo.Read(ctx, list.query)
 	if err != nil {
 		return response, ReadError
 	}
 
-	response = ReadAllStudentsResponseDto{
-		Students: students,
+	response.Students, response.NextCursor = list.page(students)
+
+	if request.WithTotal {
+		total, err := uc.StudentRepo.Count(ctx, request.Filter)
+		if err != nil {
+			return response, ReadError
+		}
+		response.Total = &total
 	}
+
 	return response, nil
 }
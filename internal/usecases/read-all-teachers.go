@@ -5,28 +5,52 @@ import (
 	"context"
 )
 
+var teacherSortKeys = sortKeys[entities.Teacher]{
+	"id":  func(t entities.Teacher) any { return t.Id },
+	"fio": func(t entities.Teacher) any { return t.Fio },
+}
+
 type ReadAllTeachersUsecase struct {
 	TeacherRepo ReadAllTeachersRepository
 }
 
+type ReadAllTeachersRequestDto struct {
+	ListRequest
+}
+
 type ReadAllTeachersResponseDto struct {
-	Teachers []entities.Teacher `json:"teachers"`
+	Teachers   []entities.Teacher `json:"teachers"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	Total      *int               `json:"total,omitempty"`
 }
 
 func NewReadAllTeachersUsecase(TeacherRepo ReadAllTeachersRepository) ReadAllTeachersUsecase {
 	return ReadAllTeachersUsecase{TeacherRepo: TeacherRepo}
 }
 
-func (uc *ReadAllTeachersUsecase) ReadAllTeachers(ctx context.Context) (ReadAllTeachersResponseDto, error) {
+func (uc *ReadAllTeachersUsecase) ReadAllTeachers(ctx context.Context, request ReadAllTeachersRequestDto) (ReadAllTeachersResponseDto, error) {
 	var response ReadAllTeachersResponseDto
 
-	teachers, err := uc.TeacherRepo.Read(ctx)
+	// no_group lists the teachers that don't teach any group
+	list, err := newListing(request.ListRequest, teacherSortKeys, "fio", "no_group")
+	if err != nil {
+		return response, err
+	}
+
+	teachers, err := uc.TeacherRepo.Read(ctx, list.query)
 	if err != nil {
 		return response, ReadError
 	}
 
-	response = ReadAllTeachersResponseDto{
-		Teachers: teachers,
+	response.Teachers, response.NextCursor = list.page(teachers)
+
+	if request.WithTotal {
+		total, err := uc.TeacherRepo.Count(ctx, request.Filter)
+		if err != nil {
+			return response, ReadError
+		}
+		response.Total = &total
 	}
+
 	return response, nil
 }
@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"time"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type ReadAuditLogUsecase struct {
+	auditRepo AuditRepository
+}
+
+type ReadAuditLogRequestDto struct {
+	ActorId    int
+	EntityType string
+	EntityId   string
+	Action     string
+	From       time.Time
+	To         time.Time
+	Limit      int
+	Offset     int
+}
+
+type ReadAuditLogResponseDto struct {
+	Entries []entities.AuditEntry `json:"entries"`
+}
+
+func NewReadAuditLogUsecase(auditRepo AuditRepository) ReadAuditLogUsecase {
+	return ReadAuditLogUsecase{auditRepo: auditRepo}
+}
+
+func (uc *ReadAuditLogUsecase) ReadAuditLog(ctx context.Context, request ReadAuditLogRequestDto) (ReadAuditLogResponseDto, error) {
+	var response ReadAuditLogResponseDto
+
+	if request.Limit < 0 || request.Offset < 0 {
+		return response, ValidationError
+	}
+	if !request.From.IsZero() && !request.To.IsZero() && !request.From.Before(request.To) {
+		return response, ValidationError
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultAuditLimit
+	}
+	if limit > maxAuditLimit {
+		limit = maxAuditLimit
+	}
+
+	entries, err := uc.auditRepo.Read(ctx, entities.AuditFilter{
+		ActorId:    request.ActorId,
+		EntityType: request.EntityType,
+		EntityId:   request.EntityId,
+		Action:     request.Action,
+		From:       request.From,
+		To:         request.To,
+		Limit:      limit,
+		Offset:     request.Offset,
+	})
+	if err != nil {
+		return response, ReadError
+	}
+
+	response = ReadAuditLogResponseDto{Entries: entries}
+	return response, nil
+}
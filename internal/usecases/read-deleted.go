@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"time"
+)
+
+// trashEntityTypes are the entity types that are soft-deleted and can be
+// restored or purged.
+var trashEntityTypes = map[string]bool{
+	entities.AuditEntityStudent: true,
+	entities.AuditEntityTeacher: true,
+	entities.AuditEntityAdmin:   true,
+	entities.AuditEntityGroup:   true,
+}
+
+type ReadDeletedUsecase struct {
+	trashRepo TrashRepository
+}
+
+type ReadDeletedRequestDto struct {
+	EntityType string
+}
+
+type ReadDeletedResponseDto struct {
+	Records []entities.DeletedRecord `json:"records"`
+}
+
+func NewReadDeletedUsecase(trashRepo TrashRepository) ReadDeletedUsecase {
+	return ReadDeletedUsecase{trashRepo: trashRepo}
+}
+
+func (uc *ReadDeletedUsecase) ReadDeleted(ctx context.Context, request ReadDeletedRequestDto) (ReadDeletedResponseDto, error) {
+	var response ReadDeletedResponseDto
+
+	if !trashEntityTypes[request.EntityType] {
+		return response, UnknownEntityTypeError
+	}
+
+	records, err := uc.trashRepo.ReadDeleted(ctx, request.EntityType, time.Time{})
+	if err != nil {
+		return response, ReadError
+	}
+
+	response = ReadDeletedResponseDto{Records: records}
+	return response, nil
+}
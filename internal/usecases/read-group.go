@@ -3,6 +3,7 @@ package usecases
 import (
 	"backendForKeenEye/internal/entities"
 	"context"
+	"errors"
 )
 
 type ReadGroupUsecase struct {
@@ -10,7 +11,8 @@ type ReadGroupUsecase struct {
 }
 
 type ReadGroupRequestDto struct {
-	Id int
+	Id             int
+	IncludeDeleted bool
 }
 
 type ReadGroupResponseDto struct {
@@ -24,7 +26,15 @@ func NewReadGroupUsecase(GroupRepo ReadGroupRepository) ReadGroupUsecase {
 func (uc *ReadGroupUsecase) ReadGroup(ctx context.Context, request ReadGroupRequestDto) (ReadGroupResponseDto, error) {
 	var response ReadGroupResponseDto
 
-	group, err := uc.GroupRepo.ReadById(ctx, request.Id)
+	read := uc.GroupRepo.ReadById
+	if request.IncludeDeleted {
+		read = uc.GroupRepo.ReadByIdIncludingDeleted
+	}
+
+	group, err := read(ctx, request.Id)
+	if errors.Is(err, entities.RecordNotFoundError) {
+		return response, NotFoundError
+	}
 	if err != nil {
 		return response, ReadError
 	}
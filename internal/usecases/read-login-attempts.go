@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"time"
+)
+
+type ReadLoginAttemptsUsecase struct {
+	repo   LoginAttemptRepository
+	window time.Duration
+}
+
+type ReadLoginAttemptsResponseDto struct {
+	Attempts []entities.LoginAttempt `json:"attempts"`
+}
+
+func NewReadLoginAttemptsUsecase(repo LoginAttemptRepository, window time.Duration) ReadLoginAttemptsUsecase {
+	return ReadLoginAttemptsUsecase{repo: repo, window: window}
+}
+
+// ReadLoginAttempts lists logins and addresses that are currently locked out
+// or have recent failed attempts.
+func (uc *ReadLoginAttemptsUsecase) ReadLoginAttempts(ctx context.Context) (ReadLoginAttemptsResponseDto, error) {
+	var response ReadLoginAttemptsResponseDto
+
+	attempts, err := uc.repo.ReadActive(ctx, time.Now().Add(-uc.window))
+	if err != nil {
+		return response, ReadError
+	}
+
+	response = ReadLoginAttemptsResponseDto{
+		Attempts: attempts,
+	}
+	return response, nil
+}
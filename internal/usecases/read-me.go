@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+)
+
+type ReadMeUsecase struct {
+	profiles    ProfileReader
+	groupRepo   ReadGroupRepository
+	teacherRepo ReadTeacherRepository
+}
+
+type ReadMeRequestDto struct {
+	User entities.User
+}
+
+type ReadMeResponseDto struct {
+	User           entities.User     `json:"user"`
+	Profile        any               `json:"profile"`
+	ProfileVersion int               `json:"-"`
+	Group          *entities.Group   `json:"group,omitempty"`
+	Teacher        *entities.Teacher `json:"teacher,omitempty"`
+}
+
+func NewReadMeUsecase(profiles ProfileReader, groupRepo ReadGroupRepository, teacherRepo ReadTeacherRepository) ReadMeUsecase {
+	return ReadMeUsecase{profiles: profiles, groupRepo: groupRepo, teacherRepo: teacherRepo}
+}
+
+// ReadMe returns the caller together with their role profile. Students also
+// get their group and its teacher.
+func (uc *ReadMeUsecase) ReadMe(ctx context.Context, request ReadMeRequestDto) (ReadMeResponseDto, error) {
+	var response ReadMeResponseDto
+
+	profile, err := uc.profiles.GetProfile(ctx, request.User)
+	if err != nil {
+		return response, UserAccountNotFoundError
+	}
+
+	response = ReadMeResponseDto{
+		User:           request.User,
+		Profile:        profile,
+		ProfileVersion: profileVersion(profile),
+	}
+
+	student, ok := profile.(entities.Student)
+	if !ok || student.GroupId == 0 {
+		return response, nil
+	}
+
+	group, err := uc.groupRepo.ReadById(ctx, student.GroupId)
+	if errors.Is(err, entities.RecordNotFoundError) {
+		return response, nil
+	}
+	if err != nil {
+		return response, ReadError
+	}
+	response.Group = &group
+
+	if group.TeacherId == 0 {
+		return response, nil
+	}
+
+	teacher, err := uc.teacherRepo.ReadById(ctx, group.TeacherId)
+	if errors.Is(err, entities.RecordNotFoundError) {
+		return response, nil
+	}
+	if err != nil {
+		return response, ReadError
+	}
+	response.Teacher = &teacher
+
+	return response, nil
+}
+
+func profileVersion(profile any) int {
+	switch p := profile.(type) {
+	case entities.Student:
+		return p.Version
+	case entities.Teacher:
+		return p.Version
+	case entities.Admin:
+		return p.Version
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+)
+
+type ReadRolesUsecase struct {
+	roleRepo RoleRepository
+}
+
+type ReadRolesResponseDto struct {
+	Roles       []entities.Role       `json:"roles"`
+	Permissions []entities.Permission `json:"permissions"`
+}
+
+func NewReadRolesUsecase(roleRepo RoleRepository) ReadRolesUsecase {
+	return ReadRolesUsecase{roleRepo: roleRepo}
+}
+
+func (uc *ReadRolesUsecase) ReadRoles(ctx context.Context) (ReadRolesResponseDto, error) {
+	roles, err := uc.roleRepo.ReadAll(ctx)
+	if err != nil {
+		return ReadRolesResponseDto{}, ReadError
+	}
+
+	permissions, err := uc.roleRepo.ReadPermissions(ctx)
+	if err != nil {
+		return ReadRolesResponseDto{}, ReadError
+	}
+
+	return ReadRolesResponseDto{Roles: roles, Permissions: permissions}, nil
+}
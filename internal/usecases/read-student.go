@@ -3,6 +3,7 @@ package usecases
 import (
 	"backendForKeenEye/internal/entities"
 	"context"
+	"errors"
 )
 
 type ReadStudentUsecase struct {
@@ -10,7 +11,8 @@ type ReadStudentUsecase struct {
 }
 
 type ReadStudentRequestDto struct {
-	Id int
+	Id             int
+	IncludeDeleted bool
 }
 
 type ReadStudentResponseDto struct {
@@ -24,7 +26,15 @@ func NewReadStudentUsecase(StudentRepo ReadStudentRepository) ReadStudentUsecase
 func (uc *ReadStudentUsecase) ReadStudent(ctx context.Context, request ReadStudentRequestDto) (ReadStudentResponseDto, error) {
 	var response ReadStudentResponseDto
 
-	student, err := uc.StudentRepo.ReadById(ctx, request.Id)
+	read := uc.StudentRepo.ReadById
+	if request.IncludeDeleted {
+		read = uc.StudentRepo.ReadByIdIncludingDeleted
+	}
+
+	student, err := read(ctx, request.Id)
+	if errors.Is(err, entities.RecordNotFoundError) {
+		return response, NotFoundError
+	}
 	if err != nil {
 		return response, ReadError
 	}
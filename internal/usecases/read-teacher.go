@@ -3,6 +3,7 @@ package usecases
 import (
 	"backendForKeenEye/internal/entities"
 	"context"
+	"errors"
 )
 
 type ReadTeacherUsecase struct {
@@ -10,7 +11,8 @@ type ReadTeacherUsecase struct {
 }
 
 type ReadTeacherRequestDto struct {
-	Id int
+	Id             int
+	IncludeDeleted bool
 }
 
 type ReadTeacherResponseDto struct {
@@ -24,7 +26,15 @@ func NewReadTeacherUsecase(TeacherRepo ReadTeacherRepository) ReadTeacherUsecase
 func (uc *ReadTeacherUsecase) ReadTeacher(ctx context.Context, request ReadTeacherRequestDto) (ReadTeacherResponseDto, error) {
 	var response ReadTeacherResponseDto
 
-	teacher, err := uc.TeacherRepo.ReadById(ctx, request.Id)
+	read := uc.TeacherRepo.ReadById
+	if request.IncludeDeleted {
+		read = uc.TeacherRepo.ReadByIdIncludingDeleted
+	}
+
+	teacher, err := read(ctx, request.Id)
+	if errors.Is(err, entities.RecordNotFoundError) {
+		return response, NotFoundError
+	}
 	if err != nil {
 		return response, ReadError
 	}
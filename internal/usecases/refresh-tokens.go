@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"context"
+	"time"
+)
+
+type RefreshTokensUsecase struct {
+	tokenRepo RefreshTokenRepository
+	userRepo  ReadUserRepository
+	jwt       JWTGenerator
+}
+
+type RefreshTokensRequestDto struct {
+	RefreshToken string
+}
+
+type RefreshTokensResponseDto struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func NewRefreshTokensUsecase(tokenRepo RefreshTokenRepository, userRepo ReadUserRepository, jwt JWTGenerator) RefreshTokensUsecase {
+	return RefreshTokensUsecase{tokenRepo: tokenRepo, userRepo: userRepo, jwt: jwt}
+}
+
+func (uc *RefreshTokensUsecase) RefreshTokens(ctx context.Context, request RefreshTokensRequestDto) (RefreshTokensResponseDto, error) {
+	var response RefreshTokensResponseDto
+
+	claims, err := uc.jwt.ParseRefreshJWT(request.RefreshToken)
+	if err != nil {
+		return response, InvalidTokenError
+	}
+
+	jti, _ := claims["jti"].(string)
+	sub, _ := claims["sub"].(float64)
+
+	stored, err := uc.tokenRepo.ReadById(ctx, jti)
+	if err != nil || stored.UserId != int(sub) || time.Now().After(stored.ExpiresAt) {
+		return response, InvalidTokenError
+	}
+
+	// A refresh token is single-use: presenting a revoked one means it has
+	// leaked, so the whole session it belongs to is killed.
+	if stored.Revoked {
+		_ = uc.tokenRepo.RevokeFamily(ctx, stored.FamilyId)
+		return response, TokenReusedError
+	}
+
+	user, err := uc.userRepo.ReadById(ctx, stored.UserId)
+	if err != nil {
+		return response, InvalidTokenError
+	}
+
+	if version, _ := claims["ver"].(float64); int(version) != user.TokenVersion {
+		return response, InvalidTokenError
+	}
+
+	tokens, refreshToken, err := generateTokenPair(uc.jwt, user, stored.FamilyId)
+	if err != nil {
+		return response, err
+	}
+
+	err = uc.tokenRepo.Rotate(ctx, stored.Id, refreshToken)
+	if err != nil {
+		// Losing the race to a concurrent refresh with the same token is
+		// reuse as well.
+		if current, readErr := uc.tokenRepo.ReadById(ctx, stored.Id); readErr == nil && current.Revoked {
+			_ = uc.tokenRepo.RevokeFamily(ctx, stored.FamilyId)
+			return response, TokenReusedError
+		}
+		return response, UpdateError
+	}
+
+	response = RefreshTokensResponseDto{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}
+	return response, nil
+}
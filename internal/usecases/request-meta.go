@@ -0,0 +1,23 @@
+package usecases
+
+import "context"
+
+type requestMetaKey struct{}
+
+// RequestMeta describes the request a usecase is executed for. It is put into
+// the context by the HTTP middlewares and read by the auditor.
+type RequestMeta struct {
+	RequestId string
+	Ip        string
+	UserId    int
+	Role      string
+}
+
+func ContextWithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+func RequestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+type RequestPasswordResetUsecase struct {
+	userRepo  ReadUserRepository
+	resetRepo PasswordResetTokenRepository
+	notifier  Notifier
+	throttler *LoginThrottler
+	tokenTTL  time.Duration
+}
+
+type RequestPasswordResetRequestDto struct {
+	Login string
+	Ip    string
+}
+
+func NewRequestPasswordResetUsecase(userRepo ReadUserRepository, resetRepo PasswordResetTokenRepository, notifier Notifier, throttler *LoginThrottler, tokenTTL time.Duration) RequestPasswordResetUsecase {
+	return RequestPasswordResetUsecase{userRepo: userRepo, resetRepo: resetRepo, notifier: notifier, throttler: throttler, tokenTTL: tokenTTL}
+}
+
+// RequestPasswordReset issues a single-use reset token and hands it to the
+// notifier. Only a hash of the token is stored. Requests are throttled per
+// client address. Apart from throttling the outcome is not reported to
+// the caller, failures included, so the endpoint can't be used to probe
+// accounts.
+func (uc *RequestPasswordResetUsecase) RequestPasswordReset(ctx context.Context, request RequestPasswordResetRequestDto) error {
+	// counted by address only, so nobody can block the reset of somebody
+	// else's account
+	err := uc.throttler.Check(ctx, "", request.Ip)
+	if err != nil {
+		return err
+	}
+	err = uc.throttler.RegisterFailure(ctx, "", request.Ip)
+	if err != nil {
+		return err
+	}
+
+	err = uc.sendResetToken(ctx, request.Login)
+	if err != nil {
+		fmt.Println("failed to send password reset token:", err)
+	}
+
+	return nil
+}
+
+func (uc *RequestPasswordResetUsecase) sendResetToken(ctx context.Context, login string) error {
+	user, err := uc.userRepo.ReadByLogin(ctx, login)
+	if err != nil {
+		return nil
+	}
+
+	token, err := newResetToken()
+	if err != nil {
+		return GenerateTokenError
+	}
+
+	err = uc.resetRepo.InvalidateByUserId(ctx, user.Id)
+	if err != nil {
+		return UpdateError
+	}
+
+	_, err = uc.resetRepo.Create(ctx, entities.PasswordResetToken{
+		UserId:    user.Id,
+		TokenHash: hashResetToken(token),
+		ExpiresAt: time.Now().Add(uc.tokenTTL),
+	})
+	if err != nil {
+		return CreateError
+	}
+
+	err = uc.notifier.SendPasswordResetToken(ctx, user.Login, user.Email, token)
+	if err != nil {
+		return fmt.Errorf("login %s: %w", user.Login, err)
+	}
+
+	return nil
+}
+
+func newResetToken() (string, error) {
+	bytes := make([]byte, 32)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
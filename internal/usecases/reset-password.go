@@ -0,0 +1,70 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+)
+
+type ResetPasswordUsecase struct {
+	userRepo  UpdatePasswordRepository
+	sessions  RevokeSessionsUsecase
+	crypto    Cryptographer
+	generator PasswordGenerator
+	tx        Transactor
+	audit     AuditRecorder
+}
+
+type ResetPasswordRequestDto struct {
+	UserId int
+}
+
+type ResetPasswordResponseDto struct {
+	TemporaryPassword string `json:"temporary_password"`
+}
+
+func NewResetPasswordUsecase(userRepo UpdatePasswordRepository, sessions RevokeSessionsUsecase, crypto Cryptographer, generator PasswordGenerator, tx Transactor, audit AuditRecorder) ResetPasswordUsecase {
+	return ResetPasswordUsecase{userRepo: userRepo, sessions: sessions, crypto: crypto, generator: generator, tx: tx, audit: audit}
+}
+
+// ResetPassword replaces the user's password with a generated one-time
+// password. The user has to change it before any other endpoint becomes
+// usable, and every session opened with the old password is ended.
+func (uc *ResetPasswordUsecase) ResetPassword(ctx context.Context, request ResetPasswordRequestDto) (ResetPasswordResponseDto, error) {
+	var response ResetPasswordResponseDto
+
+	if request.UserId == 0 {
+		return response, MissingIdError
+	}
+
+	temporaryPassword, err := uc.generator.GenerateTemporaryPassword()
+	if err != nil {
+		return response, HashPasswordError
+	}
+
+	hashedPassword, salt, err := uc.crypto.HashPassword(temporaryPassword)
+	if err != nil {
+		return response, HashPasswordError
+	}
+
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.userRepo.UpdatePassword(ctx, request.UserId, hashedPassword, salt, true)
+		if err != nil {
+			return UserNotFoundError
+		}
+
+		err = uc.sessions.RevokeSessions(ctx, RevokeSessionsRequestDto{UserId: request.UserId})
+		if err != nil {
+			return err
+		}
+
+		return uc.audit.Record(ctx, entities.AuditResetPassword, entities.AuditEntityUser, request.UserId, nil, nil)
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response = ResetPasswordResponseDto{
+		TemporaryPassword: temporaryPassword,
+	}
+	return response, nil
+}
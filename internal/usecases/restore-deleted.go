@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+)
+
+type RestoreDeletedUsecase struct {
+	trashRepo TrashRepository
+	tx        Transactor
+	audit     AuditRecorder
+}
+
+type RestoreDeletedRequestDto struct {
+	EntityType string
+	Id         int
+}
+
+func NewRestoreDeletedUsecase(trashRepo TrashRepository, tx Transactor, audit AuditRecorder) RestoreDeletedUsecase {
+	return RestoreDeletedUsecase{trashRepo: trashRepo, tx: tx, audit: audit}
+}
+
+func (uc *RestoreDeletedUsecase) RestoreDeleted(ctx context.Context, request RestoreDeletedRequestDto) error {
+	if !trashEntityTypes[request.EntityType] {
+		return UnknownEntityTypeError
+	}
+
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		record, err := uc.trashRepo.ReadDeletedById(ctx, request.EntityType, request.Id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return RecordNotFoundError
+		}
+		if err != nil {
+			return ReadError
+		}
+
+		err = uc.trashRepo.Restore(ctx, request.EntityType, request.Id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return RecordNotFoundError
+		}
+		if err != nil {
+			return repositoryError(err, UpdateError)
+		}
+
+		before := map[string]any{"is_deleted": true, "deleted_at": record.DeletedAt}
+		after := map[string]any{"is_deleted": false, "deleted_at": nil}
+		return uc.audit.Record(ctx, entities.AuditRestore, request.EntityType, request.Id, before, after)
+	})
+}
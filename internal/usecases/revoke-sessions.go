@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+)
+
+type RevokeSessionsUsecase struct {
+	userRepo  TokenVersionRepository
+	tokenRepo RevokeRefreshTokensRepository
+	tx        Transactor
+	audit     AuditRecorder
+}
+
+type RevokeSessionsRequestDto struct {
+	UserId int
+}
+
+func NewRevokeSessionsUsecase(userRepo TokenVersionRepository, tokenRepo RevokeRefreshTokensRepository, tx Transactor, audit AuditRecorder) RevokeSessionsUsecase {
+	return RevokeSessionsUsecase{userRepo: userRepo, tokenRepo: tokenRepo, tx: tx, audit: audit}
+}
+
+// RevokeSessions signs the user out everywhere: bumping the token version
+// invalidates every access token already issued, and all of the user's
+// refresh tokens are revoked so no new ones can be minted.
+func (uc *RevokeSessionsUsecase) RevokeSessions(ctx context.Context, request RevokeSessionsRequestDto) error {
+	if request.UserId == 0 {
+		return MissingIdError
+	}
+
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.userRepo.IncrementTokenVersion(ctx, request.UserId)
+		if err != nil {
+			return UserNotFoundError
+		}
+
+		err = uc.tokenRepo.RevokeByUserId(ctx, request.UserId)
+		if err != nil {
+			return repositoryError(err, UpdateError)
+		}
+
+		return uc.audit.Record(ctx, entities.AuditRevokeSessions, entities.AuditEntityUser, request.UserId, nil, nil)
+	})
+}
@@ -0,0 +1,114 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	minSearchLength    = 2
+	minPhoneDigits     = 3
+)
+
+// phoneQueryPattern matches queries that look like (a part of) a phone number.
+var phoneQueryPattern = regexp.MustCompile(`^[\d\s()+-]+$`)
+
+type SearchUsecase struct {
+	searchRepo SearchRepository
+	access     VisibilityResolver
+}
+
+type SearchRequestDto struct {
+	User  entities.User
+	Query string
+	Limit int
+}
+
+type SearchResponseDto struct {
+	Hits []entities.SearchHit `json:"hits"`
+}
+
+func NewSearchUsecase(searchRepo SearchRepository, access VisibilityResolver) SearchUsecase {
+	return SearchUsecase{searchRepo: searchRepo, access: access}
+}
+
+// Search looks the query up in the students, teachers and groups the user may
+// read and returns the best hits of all of them.
+func (uc *SearchUsecase) Search(ctx context.Context, request SearchRequestDto) (SearchResponseDto, error) {
+	var response SearchResponseDto
+
+	query := entities.SearchQuery{Text: normalizeSearch(request.Query), Limit: request.Limit}
+	if utf8.RuneCountInString(query.Text) < minSearchLength {
+		return response, ValidationError.WithFields(entities.FieldError{Field: "q", Message: "must be at least 2 characters long"})
+	}
+	if query.Limit < 0 {
+		return response, ValidationError.WithFields(entities.FieldError{Field: "limit", Message: "must not be negative"})
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultSearchLimit
+	}
+	if query.Limit > maxSearchLimit {
+		query.Limit = maxSearchLimit
+	}
+	if phoneQueryPattern.MatchString(request.Query) {
+		digits := strings.Map(keepDigit, request.Query)
+		if len(digits) >= minPhoneDigits {
+			query.Digits = digits
+		}
+	}
+
+	sources := []struct {
+		permission string
+		search     func(context.Context, entities.SearchQuery) ([]entities.SearchHit, error)
+	}{
+		{entities.StudentsReadPermission, uc.searchRepo.SearchStudents},
+		{entities.TeachersReadPermission, uc.searchRepo.SearchTeachers},
+		{entities.GroupsReadPermission, uc.searchRepo.SearchGroups},
+	}
+
+	hits := []entities.SearchHit{}
+	for _, source := range sources {
+		visibility, err := uc.access.Visibility(ctx, request.User, source.permission)
+		if err != nil {
+			return response, err
+		}
+		if visibility.None() {
+			continue
+		}
+
+		query.Visibility = visibility
+		found, err := source.search(ctx, query)
+		if err != nil {
+			return response, ReadError
+		}
+		hits = append(hits, found...)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Rank > hits[j].Rank })
+	if len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+
+	response.Hits = hits
+	return response, nil
+}
+
+// normalizeSearch prepares the query the way search_normalize prepares the
+// searched fields: lower case, ё folded into е, single spaces.
+func normalizeSearch(query string) string {
+	query = strings.ReplaceAll(strings.ToLower(query), "ё", "е")
+	return strings.Join(strings.Fields(query), " ")
+}
+
+func keepDigit(r rune) rune {
+	if r >= '0' && r <= '9' {
+		return r
+	}
+	return -1
+}
@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TokenDenylist keeps revoked access token ids in memory so that the auth
+// middleware can reject them without a database round trip. Revocations are
+// written through to Postgres; Sync reloads them periodically so that other
+// instances of the service pick them up as well.
+type TokenDenylist struct {
+	repo    RevokedTokenRepository
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+func NewTokenDenylist(repo RevokedTokenRepository) *TokenDenylist {
+	return &TokenDenylist{repo: repo, revoked: make(map[string]time.Time)}
+}
+
+func (d *TokenDenylist) Revoke(ctx context.Context, token entities.RevokedToken) error {
+	err := d.repo.Create(ctx, token)
+	if err != nil {
+		return CreateError
+	}
+
+	d.mu.Lock()
+	d.revoked[token.Id] = token.ExpiresAt
+	d.mu.Unlock()
+
+	return nil
+}
+
+func (d *TokenDenylist) IsRevoked(id string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	expiresAt, ok := d.revoked[id]
+	return ok && time.Now().Before(expiresAt)
+}
+
+func (d *TokenDenylist) Sync(ctx context.Context) error {
+	if err := d.repo.DeleteExpired(ctx); err != nil {
+		return DeleteError
+	}
+
+	tokens, err := d.repo.ReadActive(ctx)
+	if err != nil {
+		return ReadError
+	}
+
+	revoked := make(map[string]time.Time, len(tokens))
+	for _, token := range tokens {
+		revoked[token.Id] = token.ExpiresAt
+	}
+
+	d.mu.Lock()
+	d.revoked = revoked
+	d.mu.Unlock()
+
+	return nil
+}
+
+func (d *TokenDenylist) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Sync(ctx); err != nil {
+				fmt.Println("failed to sync token denylist:", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+type tokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// generateTokenPair signs a new access/refresh pair for the user and returns
+// the refresh token record the caller has to persist. An empty familyId
+// starts a new token family (a new session); rotation passes the family of
+// the token being replaced.
+func generateTokenPair(jwt JWTGenerator, user entities.User, familyId string) (tokenPair, entities.RefreshToken, error) {
+	refreshId, err := newTokenId()
+	if err != nil {
+		return tokenPair{}, entities.RefreshToken{}, GenerateTokenError
+	}
+	if familyId == "" {
+		familyId = refreshId
+	}
+
+	// "sid" ties the access token to its session (refresh token family) so
+	// that logout can end the session; "ver" lets a token version bump on
+	// the user invalidate every token issued before it.
+	accessToken, err := jwt.GenerateAccessJWT(map[string]any{"id": user.Id, "role": user.Role, "sid": familyId, "ver": user.TokenVersion})
+	if err != nil {
+		return tokenPair{}, entities.RefreshToken{}, GenerateTokenError
+	}
+
+	refreshToken, err := jwt.GenerateRefreshJWT(map[string]any{"id": user.Id, "role": user.Role, "sid": familyId, "ver": user.TokenVersion, "jti": refreshId})
+	if err != nil {
+		return tokenPair{}, entities.RefreshToken{}, GenerateTokenError
+	}
+
+	claims, err := jwt.ParseRefreshJWT(refreshToken)
+	if err != nil {
+		return tokenPair{}, entities.RefreshToken{}, GenerateTokenError
+	}
+	exp, _ := claims["exp"].(float64)
+
+	record := entities.RefreshToken{
+		Id:        refreshId,
+		UserId:    user.Id,
+		FamilyId:  familyId,
+		ExpiresAt: time.Unix(int64(exp), 0),
+	}
+
+	return tokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, record, nil
+}
+
+func newTokenId() (string, error) {
+	bytes := make([]byte, 16)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TrashRetention permanently removes records that have stayed soft-deleted
+// for longer than the retention period. Every record is purged in its own
+// transaction, so one failure does not keep the rest in the trash.
+type TrashRetention struct {
+	trashRepo TrashRepository
+	purge     *PurgeDeletedUsecase
+	ttl       time.Duration
+}
+
+func NewTrashRetention(trashRepo TrashRepository, purge *PurgeDeletedUsecase, ttl time.Duration) *TrashRetention {
+	return &TrashRetention{trashRepo: trashRepo, purge: purge, ttl: ttl}
+}
+
+// PurgeExpired purges the expired records of every entity type and returns
+// how many were removed. Records that fail are skipped and their errors
+// joined into the returned one.
+func (r *TrashRetention) PurgeExpired(ctx context.Context) (int, error) {
+	purged := 0
+	before := time.Now().Add(-r.ttl)
+	var failures []error
+
+	// groups go last so that the members purged in the same run no longer
+	// reference them
+	for _, entityType := range []string{
+		entities.AuditEntityStudent,
+		entities.AuditEntityTeacher,
+		entities.AuditEntityAdmin,
+		entities.AuditEntityGroup,
+	} {
+		records, err := r.trashRepo.ReadDeleted(ctx, entityType, before)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", entityType, ReadError))
+			continue
+		}
+
+		for _, record := range records {
+			err = r.purge.PurgeDeleted(ctx, PurgeDeletedRequestDto{EntityType: entityType, Id: record.Id})
+			if err != nil {
+				failures = append(failures, fmt.Errorf("%s %d: %w", entityType, record.Id, err))
+				continue
+			}
+			purged++
+		}
+	}
+
+	return purged, errors.Join(failures...)
+}
+
+func (r *TrashRetention) Run(ctx context.Context, interval time.Duration) {
+	if r.ttl <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.PurgeExpired(ctx); err != nil {
+				fmt.Println("failed to purge expired deleted records:", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+)
+
+type UnlockLoginUsecase struct {
+	repo  LoginAttemptRepository
+	tx    Transactor
+	audit AuditRecorder
+}
+
+type UnlockLoginRequestDto struct {
+	Login string
+	Ip    string
+}
+
+func NewUnlockLoginUsecase(repo LoginAttemptRepository, tx Transactor, audit AuditRecorder) UnlockLoginUsecase {
+	return UnlockLoginUsecase{repo: repo, tx: tx, audit: audit}
+}
+
+func (uc *UnlockLoginUsecase) UnlockLogin(ctx context.Context, request UnlockLoginRequestDto) error {
+	if request.Login == "" && request.Ip == "" {
+		return NoFieldsError
+	}
+
+	var keys []string
+	if request.Login != "" {
+		keys = append(keys, LoginAttemptKey(request.Login))
+	}
+	if request.Ip != "" {
+		keys = append(keys, IpAttemptKey(request.Ip))
+	}
+
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, key := range keys {
+			if err := uc.repo.Reset(ctx, key); err != nil {
+				return DeleteError
+			}
+
+			if err := uc.audit.Record(ctx, entities.AuditUnlock, entities.AuditEntityLoginAttempt, key, nil, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
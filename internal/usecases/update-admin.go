@@ -2,25 +2,30 @@ package usecases
 
 import (
 	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/pkg/patch"
 	"context"
+	"errors"
 )
 
 type UpdateAdminUsecase struct {
 	adminRepo UpdateAdminRepository
+	tx        Transactor
+	audit     AuditRecorder
 }
 
 type UpdateAdminRequestDto struct {
 	Id          int
-	Fio         string
-	PhoneNumber string
+	Version     int
+	Fio         patch.Field[string]
+	PhoneNumber patch.Field[string]
 }
 
 type UpdateAdminResponseDto struct {
 	Admin entities.Admin `json:"admin"`
 }
 
-func NewUpdateAdminUsecase(AdminRepo UpdateAdminRepository) UpdateAdminUsecase {
-	return UpdateAdminUsecase{adminRepo: AdminRepo}
+func NewUpdateAdminUsecase(AdminRepo UpdateAdminRepository, tx Transactor, audit AuditRecorder) UpdateAdminUsecase {
+	return UpdateAdminUsecase{adminRepo: AdminRepo, tx: tx, audit: audit}
 }
 
 func (uc *UpdateAdminUsecase) UpdateAdmin(ctx context.Context, request UpdateAdminRequestDto) (UpdateAdminResponseDto, error) {
@@ -30,19 +35,37 @@ func (uc *UpdateAdminUsecase) UpdateAdmin(ctx context.Context, request UpdateAdm
 	if request.Id == 0 {
 		return response, MissingIdError
 	}
-	if request.Fio != "" {
-		updates["fio"] = request.Fio
-	}
-	if request.PhoneNumber != "" {
-		updates["phone_number"] = request.PhoneNumber
+	if request.Fio.Cleared() {
+		return response, notNullableError("fio")
 	}
+	request.Fio.Apply(updates, "fio")
+	request.PhoneNumber.Apply(updates, "phone_number")
 	if len(updates) == 0 {
 		return response, NoFieldsError
 	}
 
-	admin, err := uc.adminRepo.Update(ctx, request.Id, updates)
+	var admin entities.Admin
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := uc.adminRepo.ReadById(ctx, request.Id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return NotFoundError
+		}
+		if err != nil {
+			return ReadError
+		}
+		if err = checkVersion(request.Version, before.Version); err != nil {
+			return err
+		}
+
+		admin, err = uc.adminRepo.Update(ctx, request.Id, before.Version, updates)
+		if err != nil {
+			return versionedWriteError(err, UpdateError)
+		}
+
+		return uc.audit.Record(ctx, entities.AuditUpdate, entities.AuditEntityAdmin, request.Id, before, admin)
+	})
 	if err != nil {
-		return response, UpdateError
+		return response, err
 	}
 	response = UpdateAdminResponseDto{
 		Admin: admin,
@@ -2,25 +2,30 @@ package usecases
 
 import (
 	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/pkg/patch"
 	"context"
+	"errors"
 )
 
 type UpdateGroupUsecase struct {
 	groupRepo UpdateGroupRepository
+	tx        Transactor
+	audit     AuditRecorder
 }
 
 type UpdateGroupRequestDto struct {
 	Id        int
-	Name      string
-	TeacherId int
+	Version   int
+	Name      patch.Field[string]
+	TeacherId patch.Field[int]
 }
 
 type UpdateGroupResponseDto struct {
 	Group entities.Group `json:"group"`
 }
 
-func NewUpdateGroupUsecase(GroupRepo UpdateGroupRepository) UpdateGroupUsecase {
-	return UpdateGroupUsecase{groupRepo: GroupRepo}
+func NewUpdateGroupUsecase(GroupRepo UpdateGroupRepository, tx Transactor, audit AuditRecorder) UpdateGroupUsecase {
+	return UpdateGroupUsecase{groupRepo: GroupRepo, tx: tx, audit: audit}
 }
 
 func (uc *UpdateGroupUsecase) UpdateGroup(ctx context.Context, request UpdateGroupRequestDto) (UpdateGroupResponseDto, error) {
@@ -30,19 +35,37 @@ func (uc *UpdateGroupUsecase) UpdateGroup(ctx context.Context, request UpdateGro
 	if request.Id == 0 {
 		return response, MissingIdError
 	}
-	if request.Name != "" {
-		updates["name"] = request.Name
-	}
-	if request.TeacherId != 0 {
-		updates["teacher_id"] = request.TeacherId
+	if request.Name.Cleared() {
+		return response, notNullableError("name")
 	}
+	request.Name.Apply(updates, "name")
+	request.TeacherId.Apply(updates, "teacher_id")
 	if len(updates) == 0 {
 		return response, NoFieldsError
 	}
 
-	group, err := uc.groupRepo.Update(ctx, request.Id, updates)
+	var group entities.Group
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := uc.groupRepo.ReadById(ctx, request.Id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return NotFoundError
+		}
+		if err != nil {
+			return ReadError
+		}
+		if err = checkVersion(request.Version, before.Version); err != nil {
+			return err
+		}
+
+		group, err = uc.groupRepo.Update(ctx, request.Id, before.Version, updates)
+		if err != nil {
+			return versionedWriteError(err, UpdateError)
+		}
+
+		return uc.audit.Record(ctx, entities.AuditUpdate, entities.AuditEntityGroup, request.Id, before, group)
+	})
 	if err != nil {
-		return response, UpdateError
+		return response, err
 	}
 	response = UpdateGroupResponseDto{
 		Group: group,
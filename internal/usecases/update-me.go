@@ -0,0 +1,130 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/pkg/patch"
+	"context"
+)
+
+type UpdateMeUsecase struct {
+	userRepo    UpdateUserRepository
+	studentRepo UpdateStudentRepository
+	teacherRepo UpdateTeacherRepository
+	adminRepo   UpdateAdminRepository
+	readMe      ReadMeUsecase
+	tx          Transactor
+	audit       AuditRecorder
+}
+
+type UpdateMeRequestDto struct {
+	User entities.User
+	// UserVersion and ProfileVersion are the versions the change is based
+	// on; zero skips the check.
+	UserVersion    int
+	ProfileVersion int
+	Fio            patch.Field[string]
+	PhoneNumber    patch.Field[string]
+	Email          patch.Field[string]
+}
+
+func NewUpdateMeUsecase(userRepo UpdateUserRepository, studentRepo UpdateStudentRepository, teacherRepo UpdateTeacherRepository, adminRepo UpdateAdminRepository, readMe ReadMeUsecase, tx Transactor, audit AuditRecorder) UpdateMeUsecase {
+	return UpdateMeUsecase{userRepo: userRepo, studentRepo: studentRepo, teacherRepo: teacherRepo, adminRepo: adminRepo, readMe: readMe, tx: tx, audit: audit}
+}
+
+// UpdateMe lets users edit their own contact details. Students can't change
+// their FIO; that is done by teachers and admins through UpdateStudent.
+func (uc *UpdateMeUsecase) UpdateMe(ctx context.Context, request UpdateMeRequestDto) (ReadMeResponseDto, error) {
+	var response ReadMeResponseDto
+	user := request.User
+	updates := make(map[string]any)
+	userUpdates := make(map[string]any)
+
+	if request.Fio.Present {
+		if user.Role == "student" {
+			return response, ForbiddenFieldError
+		}
+		if request.Fio.Cleared() {
+			return response, notNullableError("fio")
+		}
+		request.Fio.Apply(updates, "fio")
+	}
+	request.PhoneNumber.Apply(updates, "phone_number")
+	request.Email.Apply(userUpdates, "email")
+	if len(updates) == 0 && len(userUpdates) == 0 {
+		return response, NoFieldsError
+	}
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := checkVersion(request.UserVersion, user.Version); err != nil {
+			return err
+		}
+
+		if len(userUpdates) > 0 {
+			err := uc.userRepo.Update(ctx, user.Id, user.Version, userUpdates)
+			if err != nil {
+				return versionedWriteError(err, UpdateError)
+			}
+
+			err = uc.audit.Record(ctx, entities.AuditUpdate, entities.AuditEntityUser, user.Id, map[string]any{"email": user.Email}, map[string]any{"email": request.Email.OrZero()})
+			if err != nil {
+				return err
+			}
+			user.Email = request.Email.OrZero()
+			user.Version++
+		}
+
+		return uc.updateProfile(ctx, user, request.ProfileVersion, updates)
+	})
+	if err != nil {
+		return response, err
+	}
+
+	return uc.readMe.ReadMe(ctx, ReadMeRequestDto{User: user})
+}
+
+// updateProfile checks the profile version even when only the email changes,
+// so a stale If-Match is refused either way.
+func (uc *UpdateMeUsecase) updateProfile(ctx context.Context, user entities.User, version int, updates map[string]any) error {
+	var before, after any
+	var update func(version int) (any, error)
+	var entityType string
+	var err error
+
+	switch user.Role {
+	case "student":
+		entityType = entities.AuditEntityStudent
+		before, err = uc.studentRepo.ReadById(ctx, user.Id)
+		update = func(version int) (any, error) { return uc.studentRepo.Update(ctx, user.Id, version, updates) }
+	case "teacher":
+		entityType = entities.AuditEntityTeacher
+		before, err = uc.teacherRepo.ReadById(ctx, user.Id)
+		update = func(version int) (any, error) { return uc.teacherRepo.Update(ctx, user.Id, version, updates) }
+	case "admin":
+		entityType = entities.AuditEntityAdmin
+		before, err = uc.adminRepo.ReadById(ctx, user.Id)
+		update = func(version int) (any, error) { return uc.adminRepo.Update(ctx, user.Id, version, updates) }
+	default:
+		if len(updates) == 0 {
+			return nil
+		}
+		return ForbiddenFieldError
+	}
+	if err != nil {
+		return repositoryError(err, ReadError)
+	}
+
+	current := profileVersion(before)
+	if err = checkVersion(version, current); err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	after, err = update(current)
+	if err != nil {
+		return versionedWriteError(err, UpdateError)
+	}
+
+	return uc.audit.Record(ctx, entities.AuditUpdate, entityType, user.Id, before, after)
+}
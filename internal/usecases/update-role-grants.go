@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"backendForKeenEye/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+)
+
+type UpdateRoleGrantsUsecase struct {
+	roleRepo RoleRepository
+	access   *AccessControl
+	tx       Transactor
+	audit    AuditRecorder
+}
+
+type UpdateRoleGrantsRequestDto struct {
+	User   entities.User
+	Role   string
+	Grants []entities.Grant
+}
+
+func NewUpdateRoleGrantsUsecase(roleRepo RoleRepository, access *AccessControl, tx Transactor, audit AuditRecorder) UpdateRoleGrantsUsecase {
+	return UpdateRoleGrantsUsecase{roleRepo: roleRepo, access: access, tx: tx, audit: audit}
+}
+
+// UpdateRoleGrants replaces the permissions of a role. Taking the right to
+// manage roles away from one's own role is refused, so that admins cannot lock
+// everybody out of the permission editor.
+func (uc *UpdateRoleGrantsUsecase) UpdateRoleGrants(ctx context.Context, request UpdateRoleGrantsRequestDto) error {
+	seen := make(map[string]struct{}, len(request.Grants))
+	for _, grant := range request.Grants {
+		if !grant.Scope.Valid() {
+			return ValidationError
+		}
+		if _, ok := seen[grant.Permission]; ok {
+			return ValidationError
+		}
+		seen[grant.Permission] = struct{}{}
+	}
+
+	if request.Role == request.User.Role && !keepsRoleManagement(request.Grants) {
+		return SelfLockoutError
+	}
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := uc.grants(ctx, request.Role)
+		if err != nil {
+			return err
+		}
+
+		err = uc.roleRepo.ReplaceGrants(ctx, request.Role, request.Grants)
+		if err != nil {
+			switch {
+			case errors.Is(err, entities.UnknownRoleError):
+				return RoleNotFoundError
+			case errors.Is(err, entities.UnknownPermissionError):
+				return ValidationError
+			}
+			return UpdateError
+		}
+
+		return uc.audit.Record(ctx, entities.AuditUpdateGrants, entities.AuditEntityRole, request.Role, map[string]any{"grants": before}, map[string]any{"grants": request.Grants})
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = uc.access.Sync(ctx); err != nil {
+		fmt.Println("failed to reload role permissions:", err)
+	}
+
+	return nil
+}
+
+func (uc *UpdateRoleGrantsUsecase) grants(ctx context.Context, role string) ([]entities.Grant, error) {
+	roles, err := uc.roleRepo.ReadAll(ctx)
+	if err != nil {
+		return nil, ReadError
+	}
+
+	for _, r := range roles {
+		if r.Name == role {
+			return r.Grants, nil
+		}
+	}
+
+	return nil, RoleNotFoundError
+}
+
+func keepsRoleManagement(grants []entities.Grant) bool {
+	for _, grant := range grants {
+		if grant.Permission == entities.RolesManagePermission && grant.Scope == entities.ScopeAll {
+			return true
+		}
+	}
+	return false
+}
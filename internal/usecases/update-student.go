@@ -2,28 +2,43 @@ package usecases
 
 import (
 	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/pkg/patch"
 	"context"
+	"errors"
 )
 
 type UpdateStudentUsecase struct {
 	studentRepo UpdateStudentRepository
+	access      Authorizer
+	tx          Transactor
+	audit       AuditRecorder
 }
 
 type UpdateStudentRequestDto struct {
+	User        entities.User
 	Id          int
-	Fio         string
-	PhoneNumber string
-	GroupId     int
+	Version     int
+	Fio         patch.Field[string]
+	PhoneNumber patch.Field[string]
+	GroupId     patch.Field[int]
 }
 
 type UpdateStudentResponseDto struct {
 	Student entities.Student `json:"student"`
 }
 
-func NewUpdateStudentUsecase(StudentRepo UpdateStudentRepository) UpdateStudentUsecase {
-	return UpdateStudentUsecase{studentRepo: StudentRepo}
+func NewUpdateStudentUsecase(StudentRepo UpdateStudentRepository, access Authorizer, tx Transactor, audit AuditRecorder) UpdateStudentUsecase {
+	return UpdateStudentUsecase{studentRepo: StudentRepo, access: access, tx: tx, audit: audit}
 }
 
+// UpdateStudent applies the changes on behalf of request.User:
+//   - students.update on the student is needed for any change;
+//   - if it is granted only because the record is the caller's own, just the
+//     contact fields may be changed;
+//   - moving the student to another group needs students.transfer on both the
+//     current and the new group, so teachers can only move students between
+//     groups they teach; removing the student from a group needs it on the
+//     current group only.
 func (uc *UpdateStudentUsecase) UpdateStudent(ctx context.Context, request UpdateStudentRequestDto) (UpdateStudentResponseDto, error) {
 	var response UpdateStudentResponseDto
 	updates := make(map[string]any)
@@ -31,22 +46,62 @@ func (uc *UpdateStudentUsecase) UpdateStudent(ctx context.Context, request Updat
 	if request.Id == 0 {
 		return response, MissingIdError
 	}
-	if request.Fio != "" {
-		updates["fio"] = request.Fio
+
+	current, err := uc.studentRepo.ReadById(ctx, request.Id)
+	if errors.Is(err, entities.RecordNotFoundError) {
+		return response, UserAccountNotFoundError
+	}
+	if err != nil {
+		return response, ReadError
+	}
+
+	err = uc.access.Authorize(ctx, request.User, entities.StudentsUpdatePermission, Resource{OwnerId: current.Id, GroupId: current.GroupId})
+	if err != nil {
+		return response, err
+	}
+	if err = checkVersion(request.Version, current.Version); err != nil {
+		return response, err
 	}
-	if request.PhoneNumber != "" {
-		updates["phone_number"] = request.PhoneNumber
+
+	if request.Fio.Cleared() {
+		return response, notNullableError("fio")
 	}
-	if request.GroupId != 0 {
-		updates["group_id"] = request.GroupId
+	if request.Fio.Present {
+		err = uc.access.Authorize(ctx, request.User, entities.StudentsUpdatePermission, Resource{GroupId: current.GroupId})
+		if err != nil {
+			return response, ForbiddenFieldError
+		}
+		request.Fio.Apply(updates, "fio")
+	}
+	request.PhoneNumber.Apply(updates, "phone_number")
+	if request.GroupId.Present && request.GroupId.OrZero() != current.GroupId {
+		groupIds := []int{current.GroupId}
+		if !request.GroupId.Cleared() {
+			groupIds = append(groupIds, request.GroupId.Value)
+		}
+		for _, groupId := range groupIds {
+			err = uc.access.Authorize(ctx, request.User, entities.StudentsTransferPermission, Resource{GroupId: groupId})
+			if err != nil {
+				return response, TransferForbiddenError
+			}
+		}
+		request.GroupId.Apply(updates, "group_id")
 	}
 	if len(updates) == 0 {
 		return response, NoFieldsError
 	}
 
-	student, err := uc.studentRepo.Update(ctx, request.Id, updates)
+	var student entities.Student
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		student, err = uc.studentRepo.Update(ctx, request.Id, current.Version, updates)
+		if err != nil {
+			return versionedWriteError(err, UpdateError)
+		}
+
+		return uc.audit.Record(ctx, entities.AuditUpdate, entities.AuditEntityStudent, request.Id, current, student)
+	})
 	if err != nil {
-		return response, UpdateError
+		return response, err
 	}
 	response = UpdateStudentResponseDto{
 		Student: student,
@@ -2,25 +2,30 @@ package usecases
 
 import (
 	"backendForKeenEye/internal/entities"
+	"backendForKeenEye/pkg/patch"
 	"context"
+	"errors"
 )
 
 type UpdateTeacherUsecase struct {
 	teacherRepo UpdateTeacherRepository
+	tx          Transactor
+	audit       AuditRecorder
 }
 
 type UpdateTeacherRequestDto struct {
 	Id          int
-	Fio         string
-	PhoneNumber string
+	Version     int
+	Fio         patch.Field[string]
+	PhoneNumber patch.Field[string]
 }
 
 type UpdateTeacherResponseDto struct {
 	Teacher entities.Teacher `json:"teacher"`
 }
 
-func NewUpdateTeacherUsecase(TeacherRepo UpdateTeacherRepository) UpdateTeacherUsecase {
-	return UpdateTeacherUsecase{teacherRepo: TeacherRepo}
+func NewUpdateTeacherUsecase(TeacherRepo UpdateTeacherRepository, tx Transactor, audit AuditRecorder) UpdateTeacherUsecase {
+	return UpdateTeacherUsecase{teacherRepo: TeacherRepo, tx: tx, audit: audit}
 }
 
 func (uc *UpdateTeacherUsecase) UpdateTeacher(ctx context.Context, request UpdateTeacherRequestDto) (UpdateTeacherResponseDto, error) {
@@ -30,19 +35,37 @@ func (uc *UpdateTeacherUsecase) UpdateTeacher(ctx context.Context, request Updat
 	if request.Id == 0 {
 		return response, MissingIdError
 	}
-	if request.Fio != "" {
-		updates["fio"] = request.Fio
-	}
-	if request.PhoneNumber != "" {
-		updates["phone_number"] = request.PhoneNumber
+	if request.Fio.Cleared() {
+		return response, notNullableError("fio")
 	}
+	request.Fio.Apply(updates, "fio")
+	request.PhoneNumber.Apply(updates, "phone_number")
 	if len(updates) == 0 {
 		return response, NoFieldsError
 	}
 
-	teacher, err := uc.teacherRepo.Update(ctx, request.Id, updates)
+	var teacher entities.Teacher
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := uc.teacherRepo.ReadById(ctx, request.Id)
+		if errors.Is(err, entities.RecordNotFoundError) {
+			return NotFoundError
+		}
+		if err != nil {
+			return ReadError
+		}
+		if err = checkVersion(request.Version, before.Version); err != nil {
+			return err
+		}
+
+		teacher, err = uc.teacherRepo.Update(ctx, request.Id, before.Version, updates)
+		if err != nil {
+			return versionedWriteError(err, UpdateError)
+		}
+
+		return uc.audit.Record(ctx, entities.AuditUpdate, entities.AuditEntityTeacher, request.Id, before, teacher)
+	})
 	if err != nil {
-		return response, UpdateError
+		return response, err
 	}
 	response = UpdateTeacherResponseDto{
 		Teacher: teacher,
@@ -23,6 +23,10 @@ import (
 // @securityDefinitions.apikey BasicAuth
 // @in header
 // @name Authorization
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	c := container.NewContainer()
 	r := router.NewRouter(c)
@@ -2,34 +2,98 @@ package encryption_service
 
 import (
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
+	"math/big"
+	"strings"
 )
 
+const (
+	Argon2idAlgorithm = "argon2id"
+	BcryptAlgorithm   = "bcrypt"
+
+	argon2idPrefix = "$argon2id$"
+)
+
+type Params struct {
+	Algorithm         string
+	BcryptCost        int
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+	Argon2KeyLength   uint32
+	Argon2SaltLength  uint32
+}
+
+// EncryptionService hashes passwords with argon2id or bcrypt. Hashes are
+// self-describing: argon2id hashes use the PHC string format
+// ($argon2id$v=19$m=...,t=...,p=...$salt$hash) and bcrypt hashes carry their
+// own cost, so stored passwords keep verifying after the configuration
+// changes. Legacy bcrypt hashes were created with a separate salt prefixed to
+// the password; it is still accepted when comparing.
 type EncryptionService struct {
-	SaltLength int
+	params Params
 }
 
-func NewEncryptionService(salt int) *EncryptionService {
+func NewEncryptionService(params Params) *EncryptionService {
+	if params.Algorithm == "" {
+		params.Algorithm = Argon2idAlgorithm
+	}
+	if params.BcryptCost == 0 {
+		params.BcryptCost = bcrypt.DefaultCost
+	}
+
 	return &EncryptionService{
-		SaltLength: salt,
+		params: params,
 	}
 }
 
 func (e EncryptionService) HashPassword(password string) (string, string, error) {
-	salt, err := e.saltGeneration()
-	if err != nil {
-		return "", "", fmt.Errorf("failed to generate salt: %w", err)
+	if e.params.Algorithm == BcryptAlgorithm {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), e.params.BcryptCost)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to hash password: %w", err)
+		}
+		return string(hashedPassword), "", nil
 	}
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(salt+password), bcrypt.MinCost)
+
+	salt := make([]byte, e.params.Argon2SaltLength)
+	_, err := rand.Read(salt)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to hash password: %w", err)
+		return "", "", fmt.Errorf("failed to generate salt: %w", err)
 	}
-	return string(hashedPassword), salt, nil
+
+	key := argon2.IDKey([]byte(password), salt, e.params.Argon2Iterations, e.params.Argon2Memory, e.params.Argon2Parallelism, e.params.Argon2KeyLength)
+	hashedPassword := fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		e.params.Argon2Memory,
+		e.params.Argon2Iterations,
+		e.params.Argon2Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+
+	return hashedPassword, "", nil
 }
 
 func (e EncryptionService) PasswordComparison(hashedPassword, password, salt string) (bool, error) {
+	if strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		hash, err := parseArgon2idHash(hashedPassword)
+		if err != nil {
+			return false, err
+		}
+
+		key := argon2.IDKey([]byte(password), hash.salt, hash.iterations, hash.memory, hash.parallelism, uint32(len(hash.key)))
+		if subtle.ConstantTimeCompare(key, hash.key) != 1 {
+			return false, fmt.Errorf("invalid password: %w", MismatchedPasswordError)
+		}
+		return true, nil
+	}
+
 	saltPassword := salt + password
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(saltPassword))
 	if err != nil {
@@ -38,11 +102,121 @@ func (e EncryptionService) PasswordComparison(hashedPassword, password, salt str
 	return true, nil
 }
 
-func (e EncryptionService) saltGeneration() (string, error) {
-	bytes := make([]byte, e.SaltLength)
-	_, err := rand.Read(bytes)
+// NeedsRehash reports whether a stored hash was produced with another
+// algorithm or weaker parameters than the ones currently configured.
+func (e EncryptionService) NeedsRehash(hashedPassword, salt string) bool {
+	if salt != "" {
+		return true
+	}
+
+	if strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		if e.params.Algorithm != Argon2idAlgorithm {
+			return true
+		}
+
+		hash, err := parseArgon2idHash(hashedPassword)
+		if err != nil {
+			return true
+		}
+		return hash.version != argon2.Version ||
+			hash.memory != e.params.Argon2Memory ||
+			hash.iterations != e.params.Argon2Iterations ||
+			hash.parallelism != e.params.Argon2Parallelism ||
+			uint32(len(hash.key)) != e.params.Argon2KeyLength
+	}
+
+	if e.params.Algorithm != BcryptAlgorithm {
+		return true
+	}
+
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	return err != nil || cost < e.params.BcryptCost
+}
+
+type argon2idHash struct {
+	version     int
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func parseArgon2idHash(hashedPassword string) (argon2idHash, error) {
+	var hash argon2idHash
+
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 {
+		return hash, MalformedHashError
+	}
+
+	_, err := fmt.Sscanf(parts[2], "v=%d", &hash.version)
+	if err != nil {
+		return hash, MalformedHashError
+	}
+
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &hash.memory, &hash.iterations, &hash.parallelism)
+	if err != nil {
+		return hash, MalformedHashError
+	}
+
+	hash.salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return hash, MalformedHashError
+	}
+
+	hash.key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(hash.key) == 0 {
+		return hash, MalformedHashError
+	}
+
+	return hash, nil
+}
+
+const (
+	temporaryPasswordLength = 12
+	lowerLetters            = "abcdefghijkmnopqrstuvwxyz"
+	upperLetters            = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	digits                  = "23456789"
+)
+
+// GenerateTemporaryPassword returns a random password that contains at least
+// one lowercase letter, one uppercase letter and one digit. Characters that
+// are easy to confuse when read aloud or copied by hand (0/O, 1/l/I) are left
+// out.
+func (e EncryptionService) GenerateTemporaryPassword() (string, error) {
+	classes := []string{lowerLetters, upperLetters, digits}
+	alphabet := lowerLetters + upperLetters + digits
+
+	password := make([]byte, temporaryPasswordLength)
+	for i := range password {
+		set := alphabet
+		if i < len(classes) {
+			set = classes[i]
+		}
+
+		c, err := randomChar(set)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		password[i] = c
+	}
+
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+func randomChar(set string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
 	if err != nil {
-		return "", fmt.Errorf("failed to generate salt: %w", err)
+		return 0, err
 	}
-	return base64.StdEncoding.EncodeToString(bytes)[:e.SaltLength], nil
+	return set[n.Int64()], nil
 }
@@ -0,0 +1,8 @@
+package encryption_service
+
+import "errors"
+
+var (
+	MalformedHashError      = errors.New("malformed password hash")
+	MismatchedPasswordError = errors.New("password does not match hash")
+)
@@ -5,4 +5,5 @@ import "errors"
 var (
 	LifetimeIsOverError          = errors.New("lifetime is over")
 	UnexpectedSigningMethodError = errors.New("unexpected signing method")
+	WrongTokenTypeError          = errors.New("wrong token type")
 )
@@ -1,12 +1,20 @@
 package jwt_service
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 )
 
+const (
+	AccessTokenType  = "access"
+	RefreshTokenType = "refresh"
+)
+
 type JWTService struct {
 	Key         string
 	AccessTime  time.Duration
@@ -21,7 +29,7 @@ func NewJWTService(key string, accessTime, refreshTime time.Duration) *JWTServic
 	}
 }
 
-func (s *JWTService) generateJWT(data map[string]any, expiration int64) (string, error) {
+func (s *JWTService) generateJWT(data map[string]any, expiration int64, tokenType string) (string, error) {
 	claims := jwt.MapClaims{
 		"exp": expiration,
 	}
@@ -34,6 +42,15 @@ func (s *JWTService) generateJWT(data map[string]any, expiration int64) (string,
 		}
 	}
 
+	claims["typ"] = tokenType
+	if _, ok := claims["jti"]; !ok {
+		jti, err := generateTokenId()
+		if err != nil {
+			return "", err
+		}
+		claims["jti"] = jti
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	signedToken, err := token.SignedString([]byte(s.Key))
@@ -47,14 +64,35 @@ func (s *JWTService) generateJWT(data map[string]any, expiration int64) (string,
 func (s *JWTService) GenerateRefreshJWT(data map[string]any) (string, error) {
 	expiration := time.Now().Add(s.RefreshTime).Unix()
 	delete(data, "exp")
-	return s.generateJWT(data, expiration)
+	return s.generateJWT(data, expiration, RefreshTokenType)
 
 }
 
 func (s *JWTService) GenerateAccessJWT(data map[string]any) (string, error) {
 	expiration := time.Now().Add(s.AccessTime).Unix()
 	delete(data, "exp")
-	return s.generateJWT(data, expiration)
+	return s.generateJWT(data, expiration, AccessTokenType)
+}
+
+func (s *JWTService) ParseAccessJWT(tokenString string) (map[string]any, error) {
+	return s.parseTypedJWT(tokenString, AccessTokenType)
+}
+
+func (s *JWTService) ParseRefreshJWT(tokenString string) (map[string]any, error) {
+	return s.parseTypedJWT(tokenString, RefreshTokenType)
+}
+
+func (s *JWTService) parseTypedJWT(tokenString, tokenType string) (map[string]any, error) {
+	data, err := s.ParseJWT(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if typ, _ := data["typ"].(string); typ != tokenType {
+		return nil, fmt.Errorf("expected %s token: %w", tokenType, WrongTokenTypeError)
+	}
+
+	return data, nil
 }
 
 func (s *JWTService) ParseJWT(tokenString string) (map[string]any, error) {
@@ -65,6 +103,12 @@ func (s *JWTService) ParseJWT(tokenString string) (map[string]any, error) {
 		return []byte(s.Key), nil
 	})
 
+	// the signature is checked even if the claims are not valid, so a token
+	// failing on expiry alone is one we issued
+	var validationErr *jwt.ValidationError
+	if errors.As(err, &validationErr) && validationErr.Errors == jwt.ValidationErrorExpired {
+		return nil, fmt.Errorf("token expired: %w", LifetimeIsOverError)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", UnexpectedSigningMethodError)
 	}
@@ -87,3 +131,12 @@ func (s *JWTService) ParseJWT(tokenString string) (map[string]any, error) {
 
 	return data, nil
 }
+
+func generateTokenId() (string, error) {
+	bytes := make([]byte, 16)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogNotifier doesn't deliver anything: it writes notifications to a file, or
+// to stdout when no path is configured. It is meant for local development and
+// tests, where the reset link can be picked up from the log.
+type LogNotifier struct {
+	Path       string
+	LinkFormat string
+	mu         sync.Mutex
+}
+
+func NewLogNotifier(path, linkFormat string) *LogNotifier {
+	return &LogNotifier{Path: path, LinkFormat: linkFormat}
+}
+
+func (n *LogNotifier) SendPasswordResetToken(ctx context.Context, login, email, token string) error {
+	line := fmt.Sprintf("%s password reset for %q <%s>: %s\n", time.Now().Format(time.RFC3339), login, email, resetLink(n.LinkFormat, token))
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.Path == "" {
+		fmt.Print(line)
+		return nil
+	}
+
+	file, err := os.OpenFile(n.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open notification log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err = file.WriteString(line); err != nil {
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	NoRecipientError = errors.New("recipient has no email address")
+)
+
+func resetLink(linkFormat, token string) string {
+	if linkFormat == "" || !strings.Contains(linkFormat, "%s") {
+		return token
+	}
+	return fmt.Sprintf(linkFormat, token)
+}
@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     string `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+}
+
+type SMTPNotifier struct {
+	cfg        SMTPConfig
+	LinkFormat string
+}
+
+func NewSMTPNotifier(cfg SMTPConfig, linkFormat string) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg, LinkFormat: linkFormat}
+}
+
+func (n *SMTPNotifier) SendPasswordResetToken(ctx context.Context, login, email, token string) error {
+	if email == "" {
+		return NoRecipientError
+	}
+
+	var body strings.Builder
+	body.WriteString("From: " + n.cfg.From + "\r\n")
+	body.WriteString("To: " + email + "\r\n")
+	body.WriteString("Subject: KeenEye password reset\r\n")
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	body.WriteString("\r\n")
+	body.WriteString(fmt.Sprintf("Hello, %s!\r\n\r\n", login))
+	body.WriteString("Somebody requested a password reset for your KeenEye account.\r\n")
+	body.WriteString("Follow the link below to set a new password:\r\n\r\n")
+	body.WriteString(resetLink(n.LinkFormat, token) + "\r\n\r\n")
+	body.WriteString("If it wasn't you, just ignore this message.\r\n")
+
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+
+	err := smtp.SendMail(net.JoinHostPort(n.cfg.Host, n.cfg.Port), auth, n.cfg.From, []string{email}, []byte(body.String()))
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package password_policy
+
+import "strings"
+
+type PolicyError struct {
+	Violations []string
+}
+
+func (e *PolicyError) Error() string {
+	return "password " + strings.Join(e.Violations, ", ")
+}
@@ -0,0 +1,112 @@
+package password_policy
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed common-passwords.txt
+var commonPasswords []byte
+
+type Config struct {
+	MinLength        int
+	MaxLength        int
+	RequireLowercase bool
+	RequireUppercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	DenyListPath     string
+}
+
+// PasswordPolicy checks new passwords against length and character class
+// requirements, a deny-list of commonly used passwords and the user's login.
+type PasswordPolicy struct {
+	cfg    Config
+	denied map[string]struct{}
+}
+
+func NewPasswordPolicy(cfg Config) (*PasswordPolicy, error) {
+	policy := &PasswordPolicy{cfg: cfg, denied: make(map[string]struct{})}
+	policy.addDenied(commonPasswords)
+
+	if cfg.DenyListPath != "" {
+		data, err := os.ReadFile(cfg.DenyListPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read password deny-list: %w", err)
+		}
+		policy.addDenied(data)
+	}
+
+	return policy, nil
+}
+
+// Validate returns a *PolicyError listing every rule the password breaks, or
+// nil if it is acceptable.
+func (p *PasswordPolicy) Validate(login, password string) error {
+	var violations []string
+
+	length := utf8.RuneCountInString(password)
+	if length < p.cfg.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.cfg.MinLength))
+	}
+	if p.cfg.MaxLength > 0 && length > p.cfg.MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d characters long", p.cfg.MaxLength))
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r), unicode.IsSymbol(r), unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.cfg.RequireLowercase && !hasLower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.cfg.RequireUppercase && !hasUpper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.cfg.RequireDigit && !hasDigit {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.cfg.RequireSymbol && !hasSymbol {
+		violations = append(violations, "must contain a symbol")
+	}
+
+	if login != "" && strings.EqualFold(password, login) {
+		violations = append(violations, "must not be equal to the login")
+	}
+	if _, ok := p.denied[strings.ToLower(password)]; ok {
+		violations = append(violations, "is too common")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+func (p *PasswordPolicy) addDenied(data []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.denied[strings.ToLower(line)] = struct{}{}
+	}
+}
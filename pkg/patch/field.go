@@ -0,0 +1,77 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Field is a member of an RFC 7396 merge patch document. It tells apart a
+// member that is absent (leave the value unchanged), set to null (clear the
+// value) and set to a value.
+type Field[T comparable] struct {
+	Value   T
+	Present bool
+	Null    bool
+}
+
+// Set returns a field holding value.
+func Set[T comparable](value T) Field[T] {
+	return Field[T]{Value: value, Present: true}
+}
+
+// NonZero returns a field holding value, or an absent field if value is the
+// zero value. It maps bodies where an empty value means "not provided".
+func NonZero[T comparable](value T) Field[T] {
+	var zero T
+	if value == zero {
+		return Field[T]{}
+	}
+	return Set(value)
+}
+
+// UnmarshalJSON is only called for members present in the document.
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	f.Present = true
+	if bytes.Equal(data, []byte("null")) {
+		f.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+// Cleared reports whether the field is present but null or the zero value.
+func (f Field[T]) Cleared() bool {
+	var zero T
+	return f.Present && (f.Null || f.Value == zero)
+}
+
+// Apply adds the change described by the field to updates under column.
+// A cleared field sets the column to NULL.
+func (f Field[T]) Apply(updates map[string]any, column string) {
+	if !f.Present {
+		return
+	}
+	if f.Cleared() {
+		updates[column] = nil
+		return
+	}
+	updates[column] = f.Value
+}
+
+// OrZero returns the value, or the zero value if the field is absent or null.
+func (f Field[T]) OrZero() T {
+	if !f.Present || f.Null {
+		var zero T
+		return zero
+	}
+	return f.Value
+}
+
+// ValidationValue exposes the value to struct validators: absent and null
+// fields have none, so only "required"-like rules fail on them.
+func (f Field[T]) ValidationValue() any {
+	if !f.Present || f.Null {
+		return nil
+	}
+	return f.Value
+}
@@ -0,0 +1,70 @@
+package tabular
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// readCSV accepts both comma and semicolon separated files, the latter being
+// what spreadsheet programs write in locales with a decimal comma.
+func readCSV(r io.Reader) ([][]string, error) {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(bom, utf8BOM) {
+		_, _ = buffered.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = guessSeparator(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", MalformedFileError, err)
+	}
+	return rows, nil
+}
+
+// guessSeparator looks at the first line, which is the header.
+func guessSeparator(r *bufio.Reader) rune {
+	line, _ := r.Peek(r.Size())
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	if bytes.Count(line, []byte{';'}) > bytes.Count(line, []byte{','}) {
+		return ';'
+	}
+	return ','
+}
+
+// csvWriter writes UTF-8 with a byte order mark, which Excel needs to tell
+// the encoding and show Cyrillic text properly.
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	buffered := bufio.NewWriter(w)
+	_, _ = buffered.Write(utf8BOM)
+	// csv.NewWriter reuses the buffered writer, so the mark goes out with the
+	// first row
+	return &csvWriter{writer: csv.NewWriter(buffered)}
+}
+
+func (w *csvWriter) Write(row []string) error {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		cells[i] = neutralizeFormula(cell)
+	}
+	return w.writer.Write(cells)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
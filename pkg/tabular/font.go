@@ -0,0 +1,84 @@
+package tabular
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-pdf/fpdf"
+)
+
+var MalformedFontError = errors.New("malformed TrueType font")
+
+// Font is a TrueType font to embed into PDF files. It is read once and may
+// be shared by any number of writers, each of which embeds only the glyphs
+// it uses.
+type Font struct {
+	data []byte
+}
+
+// LoadFont reads a TrueType font file, such as DejaVuSans.ttf.
+func LoadFont(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFont(data)
+}
+
+// ParseFont checks that data is a TrueType font text can be set in, so that
+// a bad font is reported at startup rather than by every PDF export. The
+// font parser reads past the end of truncated files and panics on some of
+// them, so the table directory is checked first and a sample is set.
+func ParseFont(data []byte) (font *Font, err error) {
+	if err = checkFontTables(data); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			font, err = nil, fmt.Errorf("%w: %v", MalformedFontError, r)
+		}
+	}()
+
+	doc := fpdf.New("P", "pt", "A4", "")
+	doc.AddUTF8FontFromBytes(fontFamily, "", data)
+	doc.SetFont(fontFamily, "", fontSize)
+	doc.AddPage()
+	doc.Text(pageMargin, pageMargin, "Sample Образец 0123456789")
+	if err = doc.Output(io.Discard); err != nil {
+		return nil, fmt.Errorf("%w: %w", MalformedFontError, err)
+	}
+	return &Font{data: data}, nil
+}
+
+// checkFontTables checks that the file is a TrueType font whose tables,
+// those needed to set and embed text among them, lie within the file.
+func checkFontTables(data []byte) error {
+	if len(data) < 12 || !bytes.Equal(data[:4], []byte{0, 1, 0, 0}) && string(data[:4]) != "true" {
+		return fmt.Errorf("%w: not a TrueType file", MalformedFontError)
+	}
+
+	count := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+16*count {
+		return fmt.Errorf("%w: short table directory", MalformedFontError)
+	}
+	tables := make(map[string]bool, count)
+	for i := 0; i < count; i++ {
+		record := data[12+16*i:]
+		offset, length := binary.BigEndian.Uint32(record[8:]), binary.BigEndian.Uint32(record[12:])
+		if uint64(offset)+uint64(length) > uint64(len(data)) {
+			return fmt.Errorf("%w: %q table is cut off", MalformedFontError, record[:4])
+		}
+		tables[string(record[:4])] = true
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "cmap", "loca", "glyf", "name", "post"} {
+		if !tables[tag] {
+			return fmt.Errorf("%w: no %s table", MalformedFontError, tag)
+		}
+	}
+	return nil
+}
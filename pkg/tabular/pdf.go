@@ -0,0 +1,267 @@
+package tabular
+
+import (
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Page geometry and type sizes, in points.
+const (
+	pageShortSide = 595.28
+	pageLongSide  = 841.89
+	pageMargin    = 36
+
+	fontFamily  = "body"
+	fontSize    = 9
+	titleSize   = 13
+	lineHeight  = 11
+	cellPadding = 3
+
+	// sampleRows are buffered to size the columns before the first page is
+	// laid out; the rest of the rows are laid out as they come
+	sampleRows = 100
+	// a column is sized for no more text than this, longer text wraps
+	maxColumnWidth = 200
+)
+
+// pdfWriter sets the table on A4 pages, wrapping the text of the cells and
+// repeating the header on every page. The document, with the subset of the
+// font it uses, is written out on Close.
+type pdfWriter struct {
+	out    io.Writer
+	title  string
+	header []string
+	sample [][]string
+
+	doc         *fpdf.Fpdf
+	orientation string
+	widths      []float64
+	width       float64
+	height      float64
+	y           float64
+	started     bool
+}
+
+func newPDFWriter(w io.Writer, title string, font *Font) *pdfWriter {
+	doc := fpdf.New("P", "pt", "A4", "")
+	doc.SetTitle(title, true)
+	doc.SetProducer("KeenEye", true)
+	doc.SetAutoPageBreak(false, 0)
+	doc.AddUTF8FontFromBytes(fontFamily, "", font.data)
+	doc.SetFont(fontFamily, "", fontSize)
+
+	writer := &pdfWriter{out: w, title: title, doc: doc}
+	doc.SetFooterFunc(func() {
+		label := strconv.Itoa(doc.PageNo())
+		doc.Text((writer.width-doc.GetStringWidth(label))/2, writer.height-pageMargin/2, label)
+	})
+	return writer
+}
+
+func (w *pdfWriter) Write(row []string) error {
+	switch {
+	case w.header == nil:
+		w.header = printable(row)
+		return nil
+	case !w.started:
+		w.sample = append(w.sample, printable(row))
+		if len(w.sample) < sampleRows {
+			return nil
+		}
+		w.start()
+		return w.doc.Error()
+	}
+
+	w.drawRow(printable(row), false)
+	return w.doc.Error()
+}
+
+func (w *pdfWriter) Close() error {
+	if !w.started {
+		w.start()
+	}
+	return w.doc.Output(w.out)
+}
+
+// start sizes the columns by the header and the sample rows, picks the page
+// orientation and lays out the rows seen so far.
+func (w *pdfWriter) start() {
+	w.started = true
+
+	natural := make([]float64, len(w.header))
+	for _, row := range append([][]string{w.header}, w.sample...) {
+		for i := 0; i < len(row) && i < len(natural); i++ {
+			natural[i] = max(natural[i], min(w.doc.GetStringWidth(row[i])+2*cellPadding, maxColumnWidth))
+		}
+	}
+	total := 0.0
+	for _, width := range natural {
+		total += width
+	}
+
+	w.orientation, w.width, w.height = "P", pageShortSide, pageLongSide
+	if total > pageShortSide-2*pageMargin && len(natural) > 3 {
+		w.orientation, w.width, w.height = "L", pageLongSide, pageShortSide
+	}
+	w.widths = fitWidths(natural, w.width-2*pageMargin)
+
+	w.newPage()
+	for _, row := range w.sample {
+		w.drawRow(row, false)
+	}
+	w.sample = nil
+}
+
+// fitWidths shares the available width between the columns. Columns
+// narrower than an even share keep their width, the rest split what is
+// left; if everything fits the spare width is shared in proportion.
+func fitWidths(natural []float64, available float64) []float64 {
+	widths := make([]float64, len(natural))
+	if len(natural) == 0 {
+		return widths
+	}
+
+	open := make([]int, len(natural))
+	for i := range open {
+		open[i] = i
+	}
+	remaining := available
+	for len(open) > 0 {
+		share := remaining / float64(len(open))
+		var wide []int
+		for _, i := range open {
+			if natural[i] <= share {
+				widths[i] = natural[i]
+				remaining -= natural[i]
+			} else {
+				wide = append(wide, i)
+			}
+		}
+		if len(wide) == len(open) {
+			for _, i := range wide {
+				widths[i] = share
+			}
+			return widths
+		}
+		open = wide
+	}
+
+	used := available - remaining
+	for i := range widths {
+		if used > 0 {
+			widths[i] += remaining * widths[i] / used
+		}
+	}
+	return widths
+}
+
+func (w *pdfWriter) newPage() {
+	w.doc.AddPageFormat(w.orientation, fpdf.SizeType{Wd: pageShortSide, Ht: pageLongSide})
+	w.y = pageMargin
+	if w.doc.PageNo() == 1 && w.title != "" {
+		w.doc.SetFontSize(titleSize)
+		w.y += titleSize
+		w.doc.Text(pageMargin, w.y, w.title)
+		w.y += titleSize
+		w.doc.SetFontSize(fontSize)
+	}
+	w.drawRow(w.header, true)
+}
+
+// drawRow sets the cells of a row in their columns, starting a new page if
+// the row doesn't fit on this one. A row taller than a page is cut.
+func (w *pdfWriter) drawRow(row []string, header bool) {
+	lines := make([][]string, len(w.widths))
+	count := 1
+	for i := range w.widths {
+		if i < len(row) {
+			lines[i] = w.wrap(row[i], w.widths[i]-2*cellPadding)
+		}
+		count = max(count, len(lines[i]))
+	}
+
+	maxLines := int((w.height - 2*pageMargin - 2*(lineHeight+2*cellPadding)) / lineHeight)
+	count = min(count, maxLines)
+	height := float64(count)*lineHeight + 2*cellPadding
+
+	if !header && w.y+height > w.height-pageMargin-lineHeight {
+		w.newPage()
+	}
+
+	top := w.y
+	w.y += height
+	x := float64(pageMargin)
+	if header {
+		w.doc.SetFillColor(230, 230, 230)
+		w.doc.Rect(x, top, w.width-2*pageMargin, height, "F")
+	}
+	for i, width := range w.widths {
+		w.doc.Rect(x, top, width, height, "D")
+		for j := 0; j < len(lines[i]) && j < count; j++ {
+			w.doc.Text(x+cellPadding, top+cellPadding+float64(j+1)*lineHeight-2, lines[i][j])
+		}
+		x += width
+	}
+}
+
+// wrap breaks text into lines no wider than width, between words where it
+// can.
+func (w *pdfWriter) wrap(text string, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if w.doc.GetStringWidth(candidate) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			line = ""
+			for _, r := range word {
+				if line != "" && w.doc.GetStringWidth(line+string(r)) > width {
+					lines = append(lines, line)
+					line = ""
+				}
+				line += string(r)
+			}
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// printable replaces the characters the embedded font can't be addressed
+// with, those outside the Basic Multilingual Plane, turns tabs into spaces
+// and drops the other control characters but line breaks.
+func printable(row []string) []string {
+	row = slices.Clone(row)
+	for i, cell := range row {
+		row[i] = strings.Map(func(r rune) rune {
+			switch {
+			case r == '\n' || r == '\r':
+				return r
+			case r == '\t':
+				return ' '
+			case unicode.IsControl(r):
+				return -1
+			case r > 0xFFFF:
+				return unicode.ReplacementChar
+			}
+			return r
+		}, cell)
+	}
+	return row
+}
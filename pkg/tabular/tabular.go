@@ -0,0 +1,112 @@
+package tabular
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Format is a file format a table can be read from or written to.
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+	// PDF can only be written.
+	PDF Format = "pdf"
+)
+
+var (
+	UnknownFormatError = errors.New("unsupported file format")
+	MalformedFileError = errors.New("malformed file")
+	MissingFontError   = errors.New("a font is required to write PDF")
+)
+
+// ContentType returns the media type of files in the format.
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case PDF:
+		return "application/pdf"
+	}
+	return "application/octet-stream"
+}
+
+// Writer writes a table row by row, the header first. CSV rows are streamed
+// to the underlying writer as they come; XLSX and PDF files are put together
+// as the rows come and written out by Close, which completes the file.
+type Writer interface {
+	Write(row []string) error
+	Close() error
+}
+
+// Options tune the files written. Title names the XLSX worksheet and heads
+// the PDF document, which is set in Font.
+type Options struct {
+	Title string
+	Font  *Font
+}
+
+// FormatOf picks the format of a file from its name.
+func FormatOf(filename string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return CSV, nil
+	case ".xlsx":
+		return XLSX, nil
+	}
+	return "", UnknownFormatError
+}
+
+// Read returns the rows of a table: the records of a CSV file or the first
+// worksheet of an XLSX workbook. Cells are returned as text and rows may
+// have different lengths.
+func Read(r io.ReaderAt, size int64, format Format) ([][]string, error) {
+	switch format {
+	case CSV:
+		return readCSV(io.NewSectionReader(r, 0, size))
+	case XLSX:
+		return readXLSX(r, size)
+	}
+	return nil, UnknownFormatError
+}
+
+// NewWriter returns a writer of tables in the format. Nothing is written to
+// w before the first row.
+func NewWriter(w io.Writer, format Format, options Options) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(w), nil
+	case XLSX:
+		return newXLSXWriter(w, options.Title), nil
+	case PDF:
+		if options.Font == nil {
+			return nil, MissingFontError
+		}
+		return newPDFWriter(w, options.Title, options.Font), nil
+	}
+	return nil, UnknownFormatError
+}
+
+// neutralizeFormula keeps spreadsheet programs from evaluating a cell as a
+// formula by prefixing an apostrophe. Numbers, such as phone numbers in the
+// +71234567890 form, are left alone.
+func neutralizeFormula(cell string) string {
+	if cell == "" {
+		return cell
+	}
+	switch cell[0] {
+	case '=', '@', '\t', '\r':
+		return "'" + cell
+	case '+', '-':
+		if _, err := strconv.ParseFloat(cell, 64); err != nil {
+			return "'" + cell
+		}
+	}
+	return cell
+}
@@ -0,0 +1,164 @@
+package tabular
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// maxXLSXUnpackedSize bounds the unpacked size of a workbook, so that a
+	// small archive can't expand into an unbounded amount of memory or disk
+	maxXLSXUnpackedSize = 64 << 20
+
+	maxSheetNameLength = 31
+)
+
+// readXLSX reads the first worksheet of an Office Open XML workbook. Only
+// cell values are read; formulas yield their cached results. Rows missing
+// from the sheet are returned empty, so that the rows keep their numbers.
+func readXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	book, err := excelize.OpenReader(io.NewSectionReader(r, 0, size), excelize.Options{
+		RawCellValue:      true,
+		UnzipSizeLimit:    maxXLSXUnpackedSize,
+		UnzipXMLSizeLimit: maxXLSXUnpackedSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", MalformedFileError, err)
+	}
+	defer book.Close()
+
+	sheets := book.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("%w: no worksheet", MalformedFileError)
+	}
+	sheet, err := book.Rows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", MalformedFileError, err)
+	}
+	defer sheet.Close()
+
+	var rows [][]string
+	for sheet.Next() {
+		row, err := sheet.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", MalformedFileError, err)
+		}
+		rows = append(rows, row)
+	}
+	if err = sheet.Error(); err != nil {
+		return nil, fmt.Errorf("%w: %w", MalformedFileError, err)
+	}
+
+	return rows, nil
+}
+
+// xlsxWriter streams the rows into a single worksheet with a bold, frozen
+// header. The workbook is put together and written out on Close.
+type xlsxWriter struct {
+	out    io.Writer
+	book   *excelize.File
+	sheet  *excelize.StreamWriter
+	header int
+	rows   int
+	err    error
+}
+
+func newXLSXWriter(w io.Writer, title string) *xlsxWriter {
+	writer := &xlsxWriter{out: w, book: excelize.NewFile()}
+	writer.err = writer.start(sheetName(title))
+	return writer
+}
+
+func (w *xlsxWriter) start(name string) error {
+	err := w.book.SetSheetName(w.book.GetSheetName(0), name)
+	if err != nil {
+		return err
+	}
+	w.header, err = w.book.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	w.sheet, err = w.book.NewStreamWriter(name)
+	if err != nil {
+		return err
+	}
+	// the header row stays in view when scrolling
+	return w.sheet.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+}
+
+func (w *xlsxWriter) Write(row []string) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	w.rows++
+	values := make([]any, len(row))
+	for i, cell := range row {
+		switch {
+		case cell == "":
+		case w.rows == 1:
+			values[i] = excelize.Cell{StyleID: w.header, Value: cell}
+		case isXLSXNumber(cell):
+			values[i], _ = strconv.ParseInt(cell, 10, 64)
+		default:
+			values[i] = cell
+		}
+	}
+
+	ref, err := excelize.CoordinatesToCellName(1, w.rows)
+	if err == nil {
+		err = w.sheet.SetRow(ref, values)
+	}
+	w.err = err
+	return err
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.book.Close()
+	if w.err != nil {
+		return w.err
+	}
+
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.book.Write(w.out)
+}
+
+// sheetName makes a valid worksheet name of the title.
+func sheetName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(title))
+	if runes := []rune(name); len(runes) > maxSheetNameLength {
+		name = string(runes[:maxSheetNameLength])
+	}
+	name = strings.Trim(name, "'")
+	if name == "" {
+		name = "Sheet1"
+	}
+	return name
+}
+
+// isXLSXNumber tells whether a cell can be stored as a number without
+// changing how it reads: integers without leading zeros, short enough to
+// keep every digit.
+func isXLSXNumber(cell string) bool {
+	digits := strings.TrimPrefix(cell, "-")
+	if digits == "" || len(digits) > 15 || digits[0] == '0' && len(digits) > 1 {
+		return false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}